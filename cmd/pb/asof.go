@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"pebbles/internal/pebbles"
+)
+
+// snapshotCleanup removes the --as-of snapshot once a command is done.
+// exitError runs it too, since os.Exit skips deferred calls.
+var snapshotCleanup = func() {}
+
+// asOfRoot returns the root a read-only command should query: the live
+// project, or a snapshot replayed up to asOf when the flag is set. Resolve
+// anything that needs the live project, such as the local actor, first.
+func asOfRoot(root, asOf string) string {
+	if strings.TrimSpace(asOf) == "" {
+		return root
+	}
+	snapshot, cleanup, err := pebbles.OpenSnapshot(root, asOf, time.Now())
+	if err != nil {
+		exitError(err)
+	}
+	snapshotCleanup = cleanup
+	// PEBBLES_DIR would point the snapshot root back at the live log.
+	_ = os.Unsetenv("PEBBLES_DIR")
+	return snapshot
+}
@@ -47,12 +47,12 @@ func colorize(text, code string) string {
 	return code + text + ansiReset
 }
 
-// statusColor returns the ANSI color for a status value.
+// statusColor returns the ANSI color for a status value by workflow category.
 func statusColor(status string) string {
-	switch strings.ToLower(status) {
-	case pebbles.StatusInProgress:
+	switch activeWorkflow.Category(strings.ToLower(status)) {
+	case pebbles.StatusCategoryActive:
 		return ansiBrightYellow
-	case pebbles.StatusClosed:
+	case pebbles.StatusCategoryDone:
 		return ansiBrightGreen
 	default:
 		return ansiBrightWhite
@@ -78,8 +78,38 @@ func priorityColor(priority int) string {
 	}
 }
 
-// typeColor returns the ANSI color for an issue type.
+// dueColor borrows the priority colors to show how close a due date is:
+// overdue like P0, today or tomorrow like P1, this week like P2, later like P3.
+func dueColor(days int) string {
+	switch {
+	case days < 0:
+		return priorityColor(0)
+	case days <= 1:
+		return priorityColor(1)
+	case days <= 7:
+		return priorityColor(2)
+	default:
+		return priorityColor(3)
+	}
+}
+
+// typeColorCodes maps config.json color names to ANSI colors.
+var typeColorCodes = map[string]string{
+	"red":     ansiBrightRed,
+	"green":   ansiBrightGreen,
+	"yellow":  ansiBrightYellow,
+	"blue":    ansiBrightBlue,
+	"magenta": ansiBrightMagenta,
+	"cyan":    ansiBrightCyan,
+	"white":   ansiBrightWhite,
+}
+
+// typeColor returns the ANSI color for an issue type, preferring the color
+// declared in config.json.
 func typeColor(issueType string) string {
+	if declared, ok := activeIssueTypes.Lookup(issueType); ok && declared.Color != "" {
+		return typeColorCodes[declared.Color]
+	}
 	// Highlight issue types that carry extra urgency or scope.
 	switch strings.ToLower(issueType) {
 	case "bug":
@@ -97,7 +127,7 @@ func typeColor(issueType string) string {
 
 // renderStatusIcon returns a colored status icon when enabled.
 func renderStatusIcon(status string) string {
-	icon := pebbles.StatusIcon(status)
+	icon := activeWorkflow.StatusIcon(status)
 	return colorize(icon, statusColor(status))
 }
 
@@ -118,7 +148,29 @@ func renderPriorityLabel(priority int) string {
 	return colorize(label, priorityColor(priority))
 }
 
-// renderIssueType returns a colored issue type when enabled.
+// issueTypeDisplay prefixes an issue type with its configured icon.
+func issueTypeDisplay(issueType string) string {
+	if declared, ok := activeIssueTypes.Lookup(issueType); ok && declared.Icon != "" {
+		return declared.Icon + " " + issueType
+	}
+	return issueType
+}
+
+// renderIssueType returns a colored issue type with its icon when enabled.
 func renderIssueType(issueType string) string {
-	return colorize(issueType, typeColor(issueType))
+	return colorize(issueTypeDisplay(issueType), typeColor(issueType))
+}
+
+// renderLabels returns labels as "#label" tags, colored when enabled.
+func renderLabels(labels []string) string {
+	tags := make([]string, 0, len(labels))
+	for _, label := range labels {
+		tags = append(tags, colorize("#"+label, ansiCyan))
+	}
+	return strings.Join(tags, " ")
+}
+
+// renderAssignee returns the assignee as an "@who" tag, colored when enabled.
+func renderAssignee(assignee string) string {
+	return colorize("@"+assignee, ansiMagenta)
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"pebbles/internal/pebbles"
+)
+
+// doctorJSON is the machine-readable pb doctor report.
+type doctorJSON struct {
+	OK       bool                `json:"ok"`
+	Lines    int                 `json:"lines"`
+	Events   int                 `json:"events"`
+	Problems []doctorProblemJSON `json:"problems"`
+}
+
+// doctorProblemJSON describes one problem in the pb doctor report.
+type doctorProblemJSON struct {
+	Line      int    `json:"line"`
+	Code      string `json:"code"`
+	EventType string `json:"event_type,omitempty"`
+	IssueID   string `json:"issue_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// runDoctor handles pb doctor.
+func runDoctor(root string, args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	setFlagUsage(fs, doctorHelp)
+	jsonOut := fs.Bool("json", false, "Output JSON")
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		exitError(fmt.Errorf("doctor takes no arguments"))
+	}
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	report, err := pebbles.CheckEventLog(root)
+	if err != nil {
+		exitError(err)
+	}
+	if *jsonOut {
+		if err := printJSON(buildDoctorJSON(report)); err != nil {
+			exitError(err)
+		}
+	} else {
+		printDoctorReport(report)
+	}
+	// Non-zero exit lets scripts and hooks gate on a clean log.
+	if len(report.Problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+// printDoctorReport renders problems as file:line diagnostics.
+func printDoctorReport(report pebbles.LogCheckReport) {
+	if len(report.Problems) == 0 {
+		fmt.Printf("No problems found (%d events checked)\n", report.Events)
+		return
+	}
+	for _, problem := range report.Problems {
+		fmt.Println(formatDoctorProblem(problem))
+	}
+	noun := "problems"
+	if len(report.Problems) == 1 {
+		noun = "problem"
+	}
+	fmt.Printf("%d %s found in %d lines\n", len(report.Problems), noun, report.Lines)
+}
+
+// formatDoctorProblem renders a single problem line.
+func formatDoctorProblem(problem pebbles.LogProblem) string {
+	location := fmt.Sprintf("events.jsonl:%d", problem.Line)
+	code := colorize(problem.Code, ansiRed)
+	if problem.IssueID == "" {
+		return fmt.Sprintf("%s: %s: %s", location, code, problem.Message)
+	}
+	return fmt.Sprintf("%s: %s: [%s] %s", location, code, problem.IssueID, problem.Message)
+}
+
+// buildDoctorJSON converts a log check report into its JSON shape.
+func buildDoctorJSON(report pebbles.LogCheckReport) doctorJSON {
+	problems := make([]doctorProblemJSON, 0, len(report.Problems))
+	for _, problem := range report.Problems {
+		problems = append(problems, doctorProblemJSON{
+			Line:      problem.Line,
+			Code:      problem.Code,
+			EventType: problem.EventType,
+			IssueID:   problem.IssueID,
+			Message:   problem.Message,
+		})
+	}
+	return doctorJSON{
+		OK:       len(problems) == 0,
+		Lines:    report.Lines,
+		Events:   report.Events,
+		Problems: problems,
+	}
+}
@@ -14,8 +14,8 @@ Usage:
   pb --version
 
 Issue fields:
-  Status values: open, in_progress, closed (filters accept in-progress)
-  Type values: free-form; common: task, bug, feature, epic
+  Status values: open, in_progress, closed by default; config.json "statuses" adds more
+  Type values: free-form unless config.json "issue_types" declares them; common: task, bug, feature, epic
   Priority values: P0-P4 (or 0-4)
 
 Common workflows:
@@ -31,14 +31,26 @@ Working With Issues:
   create         Create a new issue
   list           List issues with filters
   show           Show issue details
+  search         Search titles, descriptions, and comments
   update         Update status or fields on an issue
   close          Close an issue
   reopen         Reopen a closed issue
-  comment        Add a comment to an issue
+  dup            Close an issue as a duplicate of another
+  delete         Hide an issue (kept in the log)
+  undelete       Restore a deleted issue
+  defer          Hide an issue from ready work until a date
+  undefer        Make a deferred issue ready again
+  start          Start a work timer on an issue
+  stop           Stop the timer and log the time worked
+  log-time       Log time worked on an issue
+  comment        Add, edit, or remove issue comments
   rename         Rename an issue id
   rename-prefix  Rename issue ids to a new prefix
   ready          Show issues ready to work (no blockers)
+  view           Run a saved view from config.json (view list shows them)
   log            Show the event log
+  doctor         Check the event log for problems
+  resolve        Clean up git merge conflict markers in the event log
 
 Import:
   import beads   Import issues from a Beads project
@@ -46,6 +58,15 @@ Import:
 Dependencies:
   dep            Manage dependencies (add, rm, tree)
 
+Labels:
+  label          Add or remove issue labels (add, rm)
+
+Assignees:
+  assign         Assign an issue to someone
+  unassign       Clear an issue's assignee
+  claim          Claim the next ready issue with a lease
+  release        Give a claim back early
+
 Prefixes:
   prefix set     Update the prefix used for new ids
 
@@ -91,11 +112,16 @@ Usage:
 Flags:
   --title <text>         Required. Example: --title "Fix login error"
   --description <text>   Optional. Markdown accepted. Example: --description "Steps to reproduce..."
-  --type <type>          Optional. Free-form; common: task, bug, feature, epic. Default: task.
-  --priority <P0-P4>     Optional. P0-P4 or 0-4 (default P2). Example: --priority P1
+  --type <type>          Optional. Free-form or a declared type; common: task, bug, feature, epic. Default: task.
+  --priority <P0-P4>     Optional. P0-P4 or 0-4 (default: the type's default_priority, else P2). Example: --priority P1
+  --field <name=value>   Optional, repeatable. Set a custom field declared in config.json. Example: --field severity=high
+  --due <date>           Optional. YYYY-MM-DD, today, tomorrow, a weekday, +Nd, or +Nw. Example: --due friday
+  --estimate <size>      Optional. Story points (3, 0.5) or time (90m, 4h, 1h30m). Example: --estimate 4h
 
 Details:
   - Generates a new issue id using the project prefix and prints it.
+  - With declared types, unknown types are rejected and a type's required fields must be set.
+  - If the project declares no task type, the first declared type is the default.
 
 Workflows:
   - Capture a quick task: pb create --title "Follow up with client"
@@ -111,25 +137,71 @@ Usage:
   pb list --type bug,feature --priority P0,P1
   pb list --stale --stale-days 14
   pb list --blocked
+  pb list --label frontend,bug
+  pb list --mine
+  pb list --where "priority <= P1 and type = bug and updated > -7d"
+  pb list --sort priority,-updated --limit 10
+  pb list --as-of 2024-03-01
   pb list --json
 
 Flags:
   --all                              Show all issues, including closed. (Default: hide closed)
-  --status <status>[,<status>...]   Filter by status (open, in_progress, closed; hyphens ok). Example: --status open,in-progress
-  --type <type>[,<type>...]         Filter by type (case-insensitive). Example: --type bug,task
+  --status <status>[,<status>...]   Filter by status or category (todo, active, done; hyphens ok). Example: --status open,in-progress
+  --type <type>[,<type>...]         Filter by type (case-insensitive; must be declared if types are). Example: --type bug,task
   --priority <P0-P4>[,<P0-P4>...]   Filter by priority (P0-P4 or 0-4). Example: --priority P0,P1
   --stale                           Show open issues with no activity. Example: --stale --stale-days 30
   --stale-days <days>               Days without activity (default 30, must be > 0). Example: --stale-days 14
   --blocked                         Show issues blocked by open dependencies. Example: --blocked
+  --label <label>[,<label>...]      Show issues carrying every listed label. Example: --label frontend,bug
+  --no-label                        Show only issues without labels. Example: --no-label
+  --assignee <who>[,<who>...]       Filter by assignee (case-insensitive). Example: --assignee dev@example.com
+  --mine                            Show issues assigned to the local actor. Example: --mine
+  --resolution <res>[,<res>...]     Show closed issues with a resolution. Example: --resolution wontfix,obsolete
+  --field <name=value>              Repeatable. Match a custom field; an empty value matches unset. Example: --field customer=acme
+  --overdue                         Show open issues whose due date has passed. Example: --overdue
+  --due-before <date>               Show issues due on or before a date (same forms as create --due). Example: --due-before +7d
+  --deferred                        Show open issues deferred past today, with their wake-up date. Example: --deferred
+  --where <expr>                    Filter with an expression (see Where expressions). Example: --where "children = 0"
+  --sort <field>[,<field>...]       Sort instead of showing the hierarchy; prefix - to reverse. Example: --sort priority,-updated
+  --limit <n>                       Show at most n issues after filtering and sorting. Example: --limit 10
+  --as-of <when>                    List issues as they were at a time or git ref (see As-of). Example: --as-of -7d
   --json                            Output JSON array of issues (includes deps). Example: --json
 
 Details:
-  - Default output includes only open and in_progress issues.
+  - Default output hides issues whose status is in the done category, unless
+    --where mentions status, resolution, or closed.
   - Status filters accept "in-progress" as an alias for "in_progress".
+  - A category name (todo, active, done) selects every status in it.
+  - --resolution implies closed issues, so --all is not needed.
+  - Sort fields: id, title, type, status, assignee, priority, created,
+    updated, closed, due, deferred, logged. Unset values sort last; ties
+    fall back to the issue ID. --sort and --limit also apply to --blocked,
+    --stale, and --json.
+
+As-of:
+  - --as-of replays the log up to a point into a throwaway cache: an RFC3339
+    timestamp, a date (through the end of that day), today, yesterday, an
+    offset such as -7d or -12h, or a git ref (the events.jsonl committed there).
+  - pb show, pb ready, and pb dep tree accept it too. The live cache is untouched.
+
+Where expressions:
+  - Comparisons are joined with and, or, not, and parentheses:
+    status in (open,in_progress) and not (label = backend or priority > P2)
+  - Operators: = != < <= > >= ~ (contains) in (...) not in (...)
+  - Text fields: id, title, description, type, status, assignee, resolution,
+    parent (case-insensitive; quote values with spaces).
+  - priority: P0-P4; P0 < P1, so "priority <= P1" means P0 or P1.
+  - created, updated, closed: -7d, -12h, -2w, today, yesterday, a date, or an
+    RFC3339 timestamp; a date covers the whole day.
+  - due, deferred: dates as for create --due, plus -Nd; "= none" is unset.
+  - logged: a duration such as 2h; children: a count; label: any label.
+  - Custom fields are referenced by name; int and date fields compare as such.
+  - An unset value matches "= ''" and "!=" anything else.
 
 Workflows:
   - Triage open bugs: pb list --status open --type bug
   - Find blocked work: pb list --blocked
+  - Focus on an area: pb list --label frontend
   - Export for scripts: pb list --json
 `
 
@@ -138,18 +210,50 @@ const showHelp = `Show issue details.
 Usage:
   pb show <id>
   pb show <id> --json
+  pb show <id> --deleted
+  pb show <id> --as-of HEAD~5
 
 Flags:
-  --json   Output JSON object (issue, deps, comments). Example: --json
+  --json            Output JSON object (issue, deps, comments). Example: --json
+  --deleted         Show the issue even if it was deleted. Example: --deleted
+  --as-of <when>    Show the issue as it was at a time or git ref (see pb list --help). Example: --as-of 2024-03-01
 
 Details:
   - Default output includes description, hierarchy, dependencies, and comments.
+  - Deleted issues are refused unless --deleted is set.
 
 Workflows:
   - Inspect an issue: pb show pb-123
   - Scriptable output: pb show pb-123 --json
 `
 
+const searchHelp = `Search issue titles, descriptions, and comments.
+
+Usage:
+  pb search <query>
+  pb search login timeout --type bug
+  pb search "auth*" --status open --json
+
+Flags:
+  --status <status>[,<status>...]   Filter by status or category. Example: --status open
+  --type <type>[,<type>...]         Filter by type. Example: --type bug,task
+  --priority <P0-P4>[,<P0-P4>...]   Filter by priority. Example: --priority P0,P1
+  --where <expr>                    Filter with an expression (see pb list --help). Example: --where "created > -30d"
+  --json                            Output JSON array of issues with a snippet field. Example: --json
+
+Details:
+  - Every word must match. Words match by stem (crash finds crashes); end a
+    word with * to match it as a prefix.
+  - Results are ranked: title matches count most, then descriptions, then
+    comments. Closed issues are included; deleted issues and comments are not.
+  - Each result shows a snippet with the matched words highlighted (wrapped
+    in ** without color and in JSON).
+  - The index is an SQLite FTS5 table rebuilt with the cache.
+
+Workflows:
+  - Find an old bug report: pb search login redirect --type bug
+`
+
 const updateHelp = `Update status or fields on an issue.
 
 Usage:
@@ -160,16 +264,21 @@ Usage:
   pb update <id> --parent pb-epic
 
 Flags:
-  --status <status>      New status (open, in_progress, closed). Example: --status in_progress
+  --status <status>      New status (any configured status). Example: --status in_progress
   --title <text>         Replace issue title. Example: --title "New title"
-  --type <type>          Replace issue type (free-form). Example: --type chore
+  --type <type>          Replace issue type (free-form or a declared type). Example: --type chore
   --description <text>   Replace description (Markdown ok). Example: --description "New details"
   --priority <P0-P4>     Replace priority (P0-P4 or 0-4). Example: --priority P0
   --parent <id|none>     Replace parent issue. Example: --parent pb-epic
+  --field <name=value>   Repeatable. Set a custom field; an empty value clears it. Example: --field severity=high
+  --due <date|none>      Set or clear the due date (same forms as create --due). Example: --due +3d
+  --estimate <size|none> Set or clear the estimate: points (3) or time (4h). Example: --estimate 5
 
 Details:
   - You can update multiple fields in one command.
   - Setting status to closed sets closed_at; other statuses clear closed_at.
+  - Status changes must follow the configured transitions, if any.
+  - Changing the type or description checks the type's required fields.
   - Clear the parent with --parent none (or --parent "").
 
 Workflows:
@@ -182,14 +291,167 @@ const closeHelp = `Close one or more issues.
 
 Usage:
   pb close <id> [<id>...]
+  pb close <id> --resolution wontfix --reason "Out of scope"
+
+Flags:
+  --resolution <resolution>   done, wontfix, duplicate, or obsolete (default done). Example: --resolution obsolete
+  --reason <text>             Why the issue was closed. Example: --reason "Replaced by the v2 API"
 
 Details:
   - Sets status to closed and updates closed_at for each issue.
+  - The resolution and reason are stored on the close event and shown by pb show.
+  - Reopening an issue clears its resolution and reason.
   - All issues are validated before any are closed.
 
 Workflows:
   - Finish work: pb close pb-123
   - Close multiple: pb close pb-123 pb-124 pb-125
+  - Decline work: pb close pb-123 --resolution wontfix --reason "Not worth the risk"
+`
+
+const dupHelp = `Close an issue as a duplicate of another issue.
+
+Usage:
+  pb dup <issue> <canonical-issue>
+
+Details:
+  - Adds a duplicate-of dependency and closes <issue> with resolution duplicate.
+  - If <canonical-issue> is itself a duplicate, the link points at the issue
+    that survives at the end of the chain.
+  - pb show lists duplicates on the canonical issue and "Duplicate of" on the
+    duplicate.
+
+Workflows:
+  - Fold a repeat bug report: pb dup pb-124 pb-123
+`
+
+const deleteHelp = `Delete one or more issues.
+
+Usage:
+  pb delete <id> [<id>...]
+  pb delete <id> --reason "Created by mistake"
+
+Flags:
+  --reason <text>   Why the issue was deleted. Example: --reason "Spam"
+
+Details:
+  - Deleted issues are hidden from list, ready, show, and dep tree.
+  - Their dependencies are detached; comments and events are kept for audit.
+  - pb show --deleted still shows the issue; pb undelete brings it back.
+
+Workflows:
+  - Remove a mistaken issue: pb delete pb-123 --reason "Created by mistake"
+`
+
+const undeleteHelp = `Restore a deleted issue.
+
+Usage:
+  pb undelete <id>
+
+Details:
+  - Clears the deletion and reattaches dependencies whose other issue is not
+    deleted.
+
+Workflows:
+  - Bring an issue back: pb undelete pb-123
+`
+
+const deferHelp = `Hide an issue from ready work until a date.
+
+Usage:
+  pb defer <id> --until <date>
+
+Flags:
+  --until <date>   Wake-up date: YYYY-MM-DD, tomorrow, a weekday, +Nd, or +Nw. Example: --until monday
+
+Details:
+  - The issue drops out of pb ready and pb claim until the date arrives; it
+    stays in pb list with a deferred:<date> tag.
+  - The date must be after today. Deferring again replaces the date.
+  - pb list --deferred shows deferred issues.
+
+Workflows:
+  - Snooze until next week: pb defer pb-123 --until +1w
+`
+
+const viewHelp = `Run or list the saved views declared in config.json.
+
+Usage:
+  pb view list
+  pb view <name>
+  pb view <name> --json
+
+Flags:
+  --json   Output JSON array of issues regardless of the view's format. Example: --json
+
+Details:
+  - Views live under "views" in .pebbles/config.json and are shared through
+    git. Personal views go in .pebbles/views.local.json ({"views": [...]}),
+    which is git-ignored; a personal view replaces a shared one of the same name.
+  - View keys: name, description, ready (start from the pb ready queue),
+    all (include done issues), mine, where (see pb list --help), sort,
+    limit, columns, and format (lines, table, or json).
+  - columns implies format table. Columns: id, title, status, type,
+    priority, assignee, labels, due, deferred, created, updated, closed,
+    estimate, logged, or a custom field name.
+  - pb view list marks personal views.
+
+Workflows:
+  - Morning triage: pb view p0-triage
+  - Feed an agent: pb view my-queue --json
+`
+
+const undeferHelp = `Make a deferred issue ready again.
+
+Usage:
+  pb undefer <id>
+
+Details:
+  - Clears the deferral before its date arrives.
+
+Workflows:
+  - Pick an issue back up early: pb undefer pb-123
+`
+
+const startHelp = `Start a work timer on an issue.
+
+Usage:
+  pb start <id>
+
+Details:
+  - Appends a timer_start event; pb stop ends the timer and logs the time.
+  - An issue has at most one running timer. Done issues cannot be timed.
+  - pb show prints the running timer; the status is left unchanged.
+
+Workflows:
+  - Time a work session: pb start pb-123, then pb stop pb-123
+`
+
+const stopHelp = `Stop the work timer on an issue and log the time worked.
+
+Usage:
+  pb stop <id>
+
+Details:
+  - Appends a work_log event with the minutes since pb start (at least 1m)
+    and prints the issue's new total.
+
+Workflows:
+  - End a work session: pb stop pb-123
+`
+
+const logTimeHelp = `Log time worked on an issue.
+
+Usage:
+  pb log-time <id> <duration>
+
+Details:
+  - Durations use minutes and hours: 45m, 2h, 1h30m.
+  - Appends a work_log event; pb show compares the total with the estimate
+    and rolls up time over child issues.
+
+Workflows:
+  - Record a meeting after the fact: pb log-time pb-123 45m
 `
 
 const reopenHelp = `Reopen a closed issue.
@@ -199,26 +461,32 @@ Usage:
 
 Details:
   - Sets status back to open and clears closed_at.
+  - Works on any issue in the done category.
 
 Workflows:
   - Reopen for follow-up: pb reopen pb-123
 `
 
-const commentHelp = `Add a comment to an issue.
+const commentHelp = `Add, edit, or remove comments on an issue.
 
 Usage:
   pb comment <id> --body "Investigated logs; suspect token refresh"
-  pb comment <id> --body "Next steps: add retry to client"
+  pb comment edit <comment-id> --body "Fixed typo"
+  pb comment rm <comment-id>
 
 Flags:
-  --body <text>   Required. Example: --body "Meeting notes..."
+  --body <text>   Required for add and edit. Example: --body "Meeting notes..."
 
 Details:
   - Wrap the body in quotes if it contains spaces or newlines.
+  - Adding a comment prints its id (c-xxxxxxxxxxxxxxxx); pb show lists the ids too.
+  - Edits keep the earlier bodies; pb show --json lists them under history.
+  - Removed comments are hidden but stay in the event log.
 
 Workflows:
   - Record progress: pb comment <id> --body "Implemented parser"
   - Capture decisions: pb comment <id> --body "Agreed to ship Friday"
+  - Fix a typo: pb comment edit c-1a2b3c4d --body "Agreed to ship Friday"
 `
 
 const importHelp = `Import issues into Pebbles.
@@ -266,11 +534,12 @@ Usage:
   pb dep tree <issue>
 
 Flags:
-  --type <blocks|parent-child>  Dependency type for add/rm. Example: --type parent-child
+  --type <blocks|parent-child|duplicate-of>  Dependency type for add/rm. Example: --type parent-child
 
 Details:
   - "blocks" means <issue> depends on <depends-on>.
   - "parent-child" builds epic/subtask hierarchies.
+  - "duplicate-of" links a duplicate to its canonical issue (see pb dup).
 
 Workflows:
   - Block a task: pb dep add pb-123 pb-456
@@ -316,6 +585,10 @@ const depTreeHelp = `Show the dependency tree for an issue.
 
 Usage:
   pb dep tree <issue>
+  pb dep tree <issue> --as-of v1.2.0
+
+Flags:
+  --as-of <when>   Show the tree as it was at a time or git ref (see pb list --help). Example: --as-of 2024-03-01
 
 Details:
   - Prints parent-child hierarchy when present; otherwise blockers.
@@ -324,20 +597,116 @@ Workflows:
   - Inspect hierarchy/blockers: pb dep tree pb-123
 `
 
+const labelHelp = `Add or remove labels on an issue.
+
+Usage:
+  pb label add <issue> <label> [label...]
+  pb label rm <issue> <label> [label...]
+
+Details:
+  - Labels are lowercased and cannot contain commas or spaces.
+  - Adding a label the issue already has (or removing one it lacks) is a no-op.
+  - Labels show as #label tags in list, ready, and show output.
+
+Workflows:
+  - Tag an issue: pb label add pb-123 frontend
+  - Drop a tag: pb label rm pb-123 frontend
+  - Filter by tag: pb list --label frontend
+`
+
+const assignHelp = `Assign an issue to someone.
+
+Usage:
+  pb assign <issue> <assignee>
+
+Details:
+  - The assignee is free-form; use the same identity as PEBBLES_ACTOR or
+    git user.email so --mine finds it.
+  - Assigning replaces any previous assignee.
+
+Workflows:
+  - Take an issue: pb assign pb-123 dev@example.com
+  - See your queue: pb ready --mine
+`
+
+const unassignHelp = `Clear the assignee on an issue.
+
+Usage:
+  pb unassign <issue>
+
+Workflows:
+  - Hand an issue back: pb unassign pb-123
+`
+
+const claimHelp = `Claim an issue for the local actor with a lease.
+
+Usage:
+  pb claim
+  pb claim <issue>
+  pb claim --lease 30m
+
+Flags:
+  --lease <duration>   How long the claim lasts (default 2h). Example: --lease 45m
+
+Details:
+  - Without an issue id, claims the first issue in pb ready order (overdue,
+    then priority, due date, and age) and prints its id.
+  - Picking and claiming happen under the project lock, so two agents never
+    claim the same issue.
+  - Claimed issues drop out of pb ready until the lease expires or the claim is
+    released; a crashed agent's claim simply runs out.
+  - The claimant is PEBBLES_ACTOR, config "actor", or git user.email.
+
+Workflows:
+  - Agent loop: id=$(pb claim) && pb show "$id"
+  - Renew a lease: pb claim pb-123 --lease 2h
+`
+
+const releaseHelp = `Give back a claim before its lease expires.
+
+Usage:
+  pb release <issue>
+  pb release <issue> --force
+
+Flags:
+  --force   Release a claim held by another actor. Example: --force
+
+Details:
+  - Releasing an unclaimed issue does nothing.
+
+Workflows:
+  - Hand work back: pb release pb-123
+`
+
 const readyHelp = `List issues that are ready to work (open and unblocked).
 
 Usage:
   pb ready
-  pb ready --json
+  pb ready --mine
+  pb ready --where "label = backend"
+  pb ready --limit 1 --json
+  pb ready --as-of yesterday
 
 Flags:
-  --json   Output JSON array of issues (includes deps). Example: --json
+  --assignee <who>[,<who>...]   Filter by assignee (case-insensitive). Example: --assignee dev@example.com
+  --mine                        Show issues assigned to the local actor. Example: --mine
+  --where <expr>                Filter with an expression (see pb list --help). Example: --where "priority <= P1"
+  --sort <field>[,<field>...]   Sort by fields instead of work order (see pb list --help). Example: --sort -updated
+  --limit <n>                   Show at most n issues. Example: --limit 5
+  --as-of <when>                Show ready work as it was at a time or git ref (see pb list --help). Example: --as-of -7d
+  --json                        Output JSON array of issues (includes deps). Example: --json
 
 Details:
-  - Ready issues are open and have no blocking dependencies.
+  - Ready issues are open, have no blocking dependencies, are not held by an
+    unexpired claim (see pb claim), and are not deferred (see pb defer).
+  - Order: overdue issues first, then priority, then earliest due date, then
+    oldest issue. --sort replaces it; JSON output uses the same order, so the
+    first element is the next thing to work on.
+  - --mine uses PEBBLES_ACTOR, config "actor", or git user.email.
 
 Workflows:
   - Daily queue: pb ready
+  - My queue: pb ready --mine
   - Scriptable output: pb ready --json
 `
 
@@ -410,6 +779,8 @@ Flags:
 Details:
   - --json outputs one JSON object per line (no pager).
   - --table prints a single line per event instead of blocks.
+  - Actor comes from the event; older events without one use git blame.
+  - New events record PEBBLES_ACTOR, config.json "actor", or git user.email.
 
 Workflows:
   - Recent activity: pb log --limit 50
@@ -417,6 +788,52 @@ Workflows:
   - Faster on large repos: pb log --no-git --table
 `
 
+const doctorHelp = `Check the event log for problems.
+
+Usage:
+  pb doctor
+  pb doctor --json
+
+Flags:
+  --json   Output a JSON report. Example: --json
+
+Details:
+  - Reports every problem with its events.jsonl line number instead of
+    stopping at the first one.
+  - Checks: malformed_json, unknown_event_type, bad_timestamp, empty_title,
+    duplicate_create, missing_issue, dangling_dep, rename_cycle,
+    rename_conflict, invalid_payload, conflict_marker.
+  - Events are checked in cache replay order, so references match what a
+    cache rebuild would see.
+  - Exits with status 1 when any problem is found.
+
+Workflows:
+  - Diagnose a failing rebuild: pb doctor
+  - Gate CI on a clean log: pb doctor --json
+`
+
+const resolveHelp = `Clean up git merge conflict markers in the event log.
+
+Usage:
+  pb resolve
+  pb resolve --dry-run
+
+Flags:
+  --dry-run   Report what would change without rewriting. Example: --dry-run
+
+Details:
+  - Keeps the event lines from both sides of every conflict block: ours
+    first, then theirs, each in file order.
+  - Drops blank lines and exact duplicates of lines already in the log.
+  - Drops the diff3 base section (the common ancestor), if present.
+  - Rewrites .pebbles/events.jsonl atomically and rebuilds the cache.
+  - Prints one summary line per conflict block.
+
+Workflows:
+  - After a conflicted merge: pb resolve && pb doctor
+  - Preview first: pb resolve --dry-run
+`
+
 const selfUpdateHelp = `Check for updates and install the latest release.
 
 Usage:
@@ -9,40 +9,80 @@ import (
 
 // issueJSON describes the JSON payload for list/ready issue output.
 type issueJSON struct {
-	ID          string   `json:"id"`
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	IssueType   string   `json:"type"`
-	Status      string   `json:"status"`
-	Priority    string   `json:"priority"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
-	ClosedAt    string   `json:"closed_at"`
-	Deps        []string `json:"deps"`
+	ID             string            `json:"id"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	IssueType      string            `json:"type"`
+	Status         string            `json:"status"`
+	Priority       string            `json:"priority"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
+	ClosedAt       string            `json:"closed_at"`
+	Resolution     string            `json:"resolution"`
+	CloseReason    string            `json:"close_reason"`
+	Assignee       string            `json:"assignee"`
+	ClaimedBy      string            `json:"claimed_by"`
+	ClaimExpiresAt string            `json:"claim_expires_at"`
+	DueAt          string            `json:"due_at"`
+	DeferredUntil  string            `json:"deferred_until"`
+	Estimate       string            `json:"estimate"`
+	LoggedMinutes  int               `json:"logged_minutes"`
+	TimerStartedAt string            `json:"timer_started_at"`
+	Labels         []string          `json:"labels"`
+	Fields         map[string]string `json:"fields"`
+	Deps           []string          `json:"deps"`
 }
 
 // issueCommentJSON represents a single comment entry in JSON output.
 type issueCommentJSON struct {
+	ID        string                `json:"id"`
+	Body      string                `json:"body"`
+	Timestamp string                `json:"timestamp"`
+	Actor     string                `json:"actor,omitempty"`
+	EditedAt  string                `json:"edited_at,omitempty"`
+	EditedBy  string                `json:"edited_by,omitempty"`
+	History   []commentRevisionJSON `json:"history"`
+}
+
+// commentRevisionJSON is an earlier body of an edited comment.
+type commentRevisionJSON struct {
 	Body      string `json:"body"`
 	Timestamp string `json:"timestamp"`
+	Actor     string `json:"actor,omitempty"`
 }
 
 // issueDetailJSON describes the JSON payload for pb show output.
 type issueDetailJSON struct {
-	ID          string             `json:"id"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	IssueType   string             `json:"type"`
-	Status      string             `json:"status"`
-	Priority    string             `json:"priority"`
-	CreatedAt   string             `json:"created_at"`
-	UpdatedAt   string             `json:"updated_at"`
-	ClosedAt    string             `json:"closed_at"`
-	Deps        []string           `json:"deps"`
-	Parents     []string           `json:"parents"`
-	Siblings    []string           `json:"siblings"`
-	Children    []string           `json:"children"`
-	Comments    []issueCommentJSON `json:"comments"`
+	ID             string             `json:"id"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	IssueType      string             `json:"type"`
+	Status         string             `json:"status"`
+	Priority       string             `json:"priority"`
+	CreatedAt      string             `json:"created_at"`
+	UpdatedAt      string             `json:"updated_at"`
+	ClosedAt       string             `json:"closed_at"`
+	Resolution     string             `json:"resolution"`
+	CloseReason    string             `json:"close_reason"`
+	Assignee       string             `json:"assignee"`
+	ClaimedBy      string             `json:"claimed_by"`
+	ClaimExpiresAt string             `json:"claim_expires_at"`
+	DueAt          string             `json:"due_at"`
+	DeferredUntil  string             `json:"deferred_until"`
+	Estimate       string             `json:"estimate"`
+	LoggedMinutes  int                `json:"logged_minutes"`
+	TimerStartedAt string             `json:"timer_started_at"`
+	DeletedAt      string             `json:"deleted_at"`
+	DeleteReason   string             `json:"delete_reason"`
+	Labels         []string           `json:"labels"`
+	Fields         map[string]string  `json:"fields"`
+	Deps           []string           `json:"deps"`
+	Parents        []string           `json:"parents"`
+	Siblings       []string           `json:"siblings"`
+	Children       []string           `json:"children"`
+	DuplicateOf    string             `json:"duplicate_of"`
+	Duplicates     []string           `json:"duplicates"`
+	Comments       []issueCommentJSON `json:"comments"`
 }
 
 // buildIssueJSON converts an issue and its deps into the list/ready JSON shape.
@@ -52,41 +92,93 @@ func buildIssueJSON(issue pebbles.Issue, deps []string) issueJSON {
 		deps = []string{}
 	}
 	return issueJSON{
-		ID:          issue.ID,
-		Title:       issue.Title,
-		Description: issue.Description,
-		IssueType:   issue.IssueType,
-		Status:      issue.Status,
-		Priority:    pebbles.PriorityLabel(issue.Priority),
-		CreatedAt:   issue.CreatedAt,
-		UpdatedAt:   issue.UpdatedAt,
-		ClosedAt:    issue.ClosedAt,
-		Deps:        deps,
+		ID:             issue.ID,
+		Title:          issue.Title,
+		Description:    issue.Description,
+		IssueType:      issue.IssueType,
+		Status:         issue.Status,
+		Priority:       pebbles.PriorityLabel(issue.Priority),
+		CreatedAt:      issue.CreatedAt,
+		UpdatedAt:      issue.UpdatedAt,
+		ClosedAt:       issue.ClosedAt,
+		Resolution:     issue.Resolution,
+		CloseReason:    issue.CloseReason,
+		Assignee:       issue.Assignee,
+		ClaimedBy:      issue.ClaimedBy,
+		ClaimExpiresAt: issue.ClaimExpiresAt,
+		DueAt:          issue.DueAt,
+		DeferredUntil:  issue.DeferredUntil,
+		Estimate:       issue.Estimate,
+		LoggedMinutes:  issue.LoggedMinutes,
+		TimerStartedAt: issue.TimerStartedAt,
+		Labels:         issueLabelsJSON(issue.Labels),
+		Fields:         issueFieldsJSON(issue.Fields),
+		Deps:           deps,
 	}
 }
 
 // buildIssueDetailJSON converts an issue, deps, and comments into show output.
-func buildIssueDetailJSON(issue pebbles.Issue, deps []string, hierarchy pebbles.IssueHierarchy, comments []pebbles.IssueComment) issueDetailJSON {
+func buildIssueDetailJSON(issue pebbles.Issue, deps []string, hierarchy pebbles.IssueHierarchy, duplicates pebbles.IssueDuplicates, comments []pebbles.IssueComment) issueDetailJSON {
 	// Mirror the list/ready fields and attach the full comment history.
 	if deps == nil {
 		deps = []string{}
 	}
 	return issueDetailJSON{
-		ID:          issue.ID,
-		Title:       issue.Title,
-		Description: issue.Description,
-		IssueType:   issue.IssueType,
-		Status:      issue.Status,
-		Priority:    pebbles.PriorityLabel(issue.Priority),
-		CreatedAt:   issue.CreatedAt,
-		UpdatedAt:   issue.UpdatedAt,
-		ClosedAt:    issue.ClosedAt,
-		Deps:        deps,
-		Parents:     issueIDsFromIssues(hierarchy.Parents),
-		Siblings:    issueIDsFromIssues(hierarchy.Siblings),
-		Children:    issueIDsFromIssues(hierarchy.Children),
-		Comments:    buildIssueCommentsJSON(comments),
+		ID:             issue.ID,
+		Title:          issue.Title,
+		Description:    issue.Description,
+		IssueType:      issue.IssueType,
+		Status:         issue.Status,
+		Priority:       pebbles.PriorityLabel(issue.Priority),
+		CreatedAt:      issue.CreatedAt,
+		UpdatedAt:      issue.UpdatedAt,
+		ClosedAt:       issue.ClosedAt,
+		Resolution:     issue.Resolution,
+		CloseReason:    issue.CloseReason,
+		Assignee:       issue.Assignee,
+		ClaimedBy:      issue.ClaimedBy,
+		ClaimExpiresAt: issue.ClaimExpiresAt,
+		DueAt:          issue.DueAt,
+		DeferredUntil:  issue.DeferredUntil,
+		Estimate:       issue.Estimate,
+		LoggedMinutes:  issue.LoggedMinutes,
+		TimerStartedAt: issue.TimerStartedAt,
+		DeletedAt:      issue.DeletedAt,
+		DeleteReason:   issue.DeleteReason,
+		Labels:         issueLabelsJSON(issue.Labels),
+		Fields:         issueFieldsJSON(issue.Fields),
+		Deps:           deps,
+		Parents:        issueIDsFromIssues(hierarchy.Parents),
+		Siblings:       issueIDsFromIssues(hierarchy.Siblings),
+		Children:       issueIDsFromIssues(hierarchy.Children),
+		DuplicateOf:    duplicateOfID(duplicates),
+		Duplicates:     issueIDsFromIssues(duplicates.Duplicates),
+		Comments:       buildIssueCommentsJSON(comments),
+	}
+}
+
+// issueFieldsJSON returns custom fields as a non-nil map so JSON emits an object.
+func issueFieldsJSON(fields map[string]string) map[string]string {
+	if fields == nil {
+		return map[string]string{}
+	}
+	return fields
+}
+
+// issueLabelsJSON returns labels as a non-nil slice so JSON emits an array.
+func issueLabelsJSON(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+// duplicateOfID returns the surviving issue ID for a duplicate, or "".
+func duplicateOfID(duplicates pebbles.IssueDuplicates) string {
+	if duplicates.Canonical == nil {
+		return ""
 	}
+	return duplicates.Canonical.ID
 }
 
 // issueIDsFromIssues extracts issue IDs for JSON output.
@@ -108,9 +200,22 @@ func buildIssueCommentsJSON(comments []pebbles.IssueComment) []issueCommentJSON
 	}
 	converted := make([]issueCommentJSON, 0, len(comments))
 	for _, comment := range comments {
+		history := make([]commentRevisionJSON, 0, len(comment.History))
+		for _, revision := range comment.History {
+			history = append(history, commentRevisionJSON{
+				Body:      revision.Body,
+				Timestamp: revision.Timestamp,
+				Actor:     revision.Actor,
+			})
+		}
 		converted = append(converted, issueCommentJSON{
+			ID:        comment.ID,
 			Body:      comment.Body,
 			Timestamp: comment.Timestamp,
+			Actor:     comment.Actor,
+			EditedAt:  comment.EditedAt,
+			EditedBy:  comment.EditedBy,
+			History:   history,
 		})
 	}
 	return converted
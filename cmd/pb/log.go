@@ -75,6 +75,7 @@ type logJSON struct {
 	ActorDate  string            `json:"actor_date"`
 	Details    string            `json:"details,omitempty"`
 	Payload    map[string]string `json:"payload,omitempty"`
+	Raw        string            `json:"raw,omitempty"`
 }
 
 type gitAttribution struct {
@@ -151,7 +152,8 @@ func runLog(root string, args []string) {
 		filtered = filtered[:limit]
 	}
 	var attributions []gitAttribution
-	if !*noGit {
+	// Blame is only a fallback for older events that carry no actor.
+	if !*noGit && needsBlame(filtered) {
 		attributions, err = gitBlameAttributions(root, pebbles.EventsPath(root))
 		if err != nil {
 			attributions = nil
@@ -160,7 +162,7 @@ func runLog(root string, args []string) {
 	// JSON output is streamed directly to stdout (no pager).
 	if *jsonOut {
 		for _, entry := range filtered {
-			attribution := attributionForLine(attributions, entry.Entry.Line)
+			attribution := attributionForEntry(attributions, entry)
 			event := enrichEvent(entry.Entry.Event, descriptions)
 			line := logLine{
 				Actor:      attribution.Author,
@@ -180,7 +182,7 @@ func runLog(root string, args []string) {
 	// Build formatted output before writing to a pager or stdout.
 	var output strings.Builder
 	for index, entry := range filtered {
-		attribution := attributionForLine(attributions, entry.Entry.Line)
+		attribution := attributionForEntry(attributions, entry)
 		event := enrichEvent(entry.Entry.Event, descriptions)
 		line := logLine{
 			Actor:      attribution.Author,
@@ -372,9 +374,11 @@ func logEventDetails(event pebbles.Event) string {
 		}
 		return strings.Join(parts, " ")
 	case pebbles.EventTypeClose:
+		parts := closeDetailLines(event)
 		if description := event.Payload["description"]; description != "" {
-			return fmt.Sprintf("description=%s", formatPayloadValue("description", description))
+			parts = append(parts, fmt.Sprintf("description=%s", formatPayloadValue("description", description)))
 		}
+		return strings.Join(parts, " ")
 	case pebbles.EventTypeComment:
 		if body := event.Payload["body"]; body != "" {
 			return fmt.Sprintf("body=%s", formatPayloadValue("body", body))
@@ -391,6 +395,8 @@ func logEventDetails(event pebbles.Event) string {
 			}
 		}
 		return strings.Join(parts, " ")
+	case pebbles.EventTypeFieldSet:
+		return fieldSetDetail(event)
 	default:
 		return formatPayloadPairs(event.Payload)
 	}
@@ -434,7 +440,10 @@ func logEventDetailSections(event pebbles.Event) logDetailSections {
 			Description: logDetailDescription(event.Payload["description"]),
 		}
 	case pebbles.EventTypeClose:
-		return logDetailSections{Description: logDetailDescription(event.Payload["description"])}
+		return logDetailSections{
+			Lines:       closeDetailLines(event),
+			Description: logDetailDescription(event.Payload["description"]),
+		}
 	case pebbles.EventTypeComment:
 		return logDetailSections{Description: logDetailDescription(event.Payload["body"])}
 	case pebbles.EventTypeDepAdd, pebbles.EventTypeDepRemove:
@@ -449,12 +458,34 @@ func logEventDetailSections(event pebbles.Event) logDetailSections {
 			}
 		}
 		return logDetailSections{Lines: lines}
+	case pebbles.EventTypeFieldSet:
+		return logDetailSections{Lines: []string{fieldSetDetail(event)}}
 	default:
 		return logDetailSections{Lines: formatPayloadLines(event.Payload)}
 	}
 	return logDetailSections{}
 }
 
+// fieldSetDetail renders a field_set event as name=value.
+func fieldSetDetail(event pebbles.Event) string {
+	if event.Payload["value"] == "" {
+		return fmt.Sprintf("%s=(cleared)", event.Payload["field"])
+	}
+	return fmt.Sprintf("%s=%s", event.Payload["field"], formatPayloadValue("value", event.Payload["value"]))
+}
+
+// closeDetailLines renders the resolution and reason recorded on a close event.
+func closeDetailLines(event pebbles.Event) []string {
+	var lines []string
+	if resolution := event.Payload["resolution"]; resolution != "" {
+		lines = append(lines, fmt.Sprintf("resolution=%s", resolution))
+	}
+	if reason := event.Payload["reason"]; reason != "" {
+		lines = append(lines, fmt.Sprintf("reason=%s", formatPayloadValue("reason", reason)))
+	}
+	return lines
+}
+
 // logDetailDescription normalizes description/body text for log rendering.
 func logDetailDescription(value string) string {
 	if strings.TrimSpace(value) == "" {
@@ -549,7 +580,7 @@ func logEventTypeColor(eventType string) string {
 		return ansiBrightCyan
 	case "dep_add":
 		return ansiBrightWhite
-	case "dep_rm":
+	case "dep_rm", "delete":
 		return ansiBrightRed
 	default:
 		return ansiBrightWhite
@@ -610,7 +641,7 @@ func renderLogDetailValue(key, value string) string {
 		}
 		return renderPriorityLabel(priority)
 	case "type":
-		return renderIssueType(value)
+		return colorize(value, typeColor(value))
 	case "depends_on":
 		return renderLogIssueID(value)
 	default:
@@ -627,6 +658,10 @@ func formatPrettyLog(entry logEntry, line logLine) string {
 	output.WriteString(fmt.Sprintf("%s %s\n", renderLogLabel("Title:"), colorize(line.IssueTitle, ansiBold+ansiBrightWhite)))
 	output.WriteString(fmt.Sprintf("%s  %s\n", renderLogLabel("When:"), renderLogValue(line.EventTime)))
 	output.WriteString(fmt.Sprintf("%s %s (%s)\n", renderLogLabel("Actor:"), renderLogValue(line.Actor), renderLogValue(line.ActorDate)))
+	// Events this version cannot apply keep their raw line for inspection.
+	if raw := unknownEventRaw(entry); raw != "" {
+		output.WriteString(fmt.Sprintf("%s   %s\n", renderLogLabel("Raw:"), renderLogValue(raw)))
+	}
 	// Render payload details with indentation or an explicit none marker.
 	details := logEventDetailSections(entry.Entry.Event)
 	if len(details.Lines) == 0 && details.Description == "" {
@@ -660,6 +695,14 @@ func formatPrettyLog(entry logEntry, line logLine) string {
 	return output.String()
 }
 
+// unknownEventRaw returns the raw log line for event types pb cannot apply.
+func unknownEventRaw(entry logEntry) string {
+	if pebbles.IsKnownEventType(entry.Entry.Event.Type) {
+		return ""
+	}
+	return entry.Entry.Raw
+}
+
 // formatLogLine renders columns with padding and optional details.
 func formatLogLine(line logLine, widths logColumnWidths) string {
 	columns := []string{
@@ -782,6 +825,7 @@ func printLogJSON(entry logEntry, line logLine) error {
 		ActorDate:  line.ActorDate,
 		Details:    line.Details,
 		Payload:    payload,
+		Raw:        unknownEventRaw(entry),
 	}
 	data, err := json.Marshal(record)
 	if err != nil {
@@ -891,6 +935,29 @@ func parseGitTZ(value string) (int, bool) {
 	return offset, true
 }
 
+// needsBlame reports whether any entry lacks a recorded actor.
+func needsBlame(entries []logEntry) bool {
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.Entry.Event.Actor) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// attributionForEntry prefers the event's recorded actor over git blame.
+func attributionForEntry(attributions []gitAttribution, entry logEntry) gitAttribution {
+	actor := strings.TrimSpace(entry.Entry.Event.Actor)
+	if actor == "" {
+		return attributionForLine(attributions, entry.Entry.Line)
+	}
+	date := "unknown"
+	if entry.ParsedOK {
+		date = entry.ParsedTime.UTC().Format("2006-01-02")
+	}
+	return gitAttribution{Author: actor, Date: date}
+}
+
 // attributionForLine returns blame metadata for a line or defaults.
 func attributionForLine(attributions []gitAttribution, line int) gitAttribution {
 	if line <= 0 || line > len(attributions) {
@@ -84,6 +84,14 @@ func TestLogEventDetails(t *testing.T) {
 	if got := logEventDetails(comment); got != `body="Needs attention"` {
 		t.Fatalf("comment details mismatch: %q", got)
 	}
+	fieldSet := pebbles.NewFieldSetEvent("pb-1", "customer", "Acme Corp", "2024-01-01T00:00:00Z")
+	if got := logEventDetails(fieldSet); got != `customer="Acme Corp"` {
+		t.Fatalf("field_set details mismatch: %q", got)
+	}
+	fieldClear := pebbles.NewFieldSetEvent("pb-1", "customer", "", "2024-01-01T00:00:00Z")
+	if got := logEventDetails(fieldClear); got != "customer=(cleared)" {
+		t.Fatalf("field_set clear details mismatch: %q", got)
+	}
 	unknown := pebbles.Event{
 		Type: "unknown_type",
 		Payload: map[string]string{
@@ -357,3 +365,54 @@ func TestEnrichEventAddsDescription(t *testing.T) {
 		t.Fatalf("expected description to be enriched, got %+v", enriched.Payload)
 	}
 }
+
+// TestFormatPrettyLogShowsRawForUnknownTypes keeps events from newer pb versions visible.
+func TestFormatPrettyLogShowsRawForUnknownTypes(t *testing.T) {
+	previous := colorEnabled
+	colorEnabled = false
+	defer func() {
+		colorEnabled = previous
+	}()
+
+	raw := `{"version":2,"type":"teleport","issue_id":"pb-1","payload":{"to":"mars"}}`
+	entry := logEntry{
+		Entry: pebbles.EventLogEntry{
+			Line:  9,
+			Event: pebbles.Event{Version: 2, Type: "teleport", IssueID: "pb-1", Payload: map[string]string{"to": "mars"}},
+			Raw:   raw,
+		},
+	}
+	line := logLine{EventType: "teleport", IssueID: "pb-1", IssueTitle: "Future"}
+	output := formatPrettyLog(entry, line)
+	if !strings.Contains(output, "Raw:   "+raw) {
+		t.Fatalf("missing raw line: %q", output)
+	}
+	if !strings.Contains(output, "to=mars") {
+		t.Fatalf("missing payload details: %q", output)
+	}
+}
+
+// TestAttributionForEntryPrefersEventActor falls back to blame only without an actor.
+func TestAttributionForEntryPrefersEventActor(t *testing.T) {
+	attributions := []gitAttribution{{Author: "Blame Author", Date: "2026-01-01"}}
+	withActor := logEntry{
+		Entry: pebbles.EventLogEntry{
+			Line:  1,
+			Event: pebbles.Event{Type: pebbles.EventTypeCreate, Actor: "agent@example.com"},
+		},
+		ParsedTime: time.Date(2026, 1, 19, 10, 0, 0, 0, time.UTC),
+		ParsedOK:   true,
+	}
+	got := attributionForEntry(attributions, withActor)
+	if got.Author != "agent@example.com" || got.Date != "2026-01-19" {
+		t.Fatalf("expected event actor, got %+v", got)
+	}
+	legacy := logEntry{Entry: pebbles.EventLogEntry{Line: 1, Event: pebbles.Event{Type: pebbles.EventTypeCreate}}}
+	got = attributionForEntry(attributions, legacy)
+	if got.Author != "Blame Author" {
+		t.Fatalf("expected blame fallback, got %+v", got)
+	}
+	if needsBlame([]logEntry{withActor}) || !needsBlame([]logEntry{withActor, legacy}) {
+		t.Fatalf("unexpected needsBlame result")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -19,6 +20,15 @@ var (
 	buildDate    = "unknown"
 )
 
+// activeWorkflow holds the project's status workflow, loaded once in main.
+var activeWorkflow = pebbles.DefaultWorkflow()
+
+// activeIssueTypes holds the project's declared issue types, loaded once in main.
+var activeIssueTypes pebbles.IssueTypes
+
+// activeFields holds the project's declared custom fields, loaded once in main.
+var activeFields pebbles.CustomFields
+
 // main dispatches pb subcommands.
 func main() {
 	root, err := os.Getwd()
@@ -36,40 +46,92 @@ func main() {
 	}
 	cmd := os.Args[1]
 	args := os.Args[2:]
+	// Load the configured statuses and types before any command uses them.
+	if cmd != "init" && cmd != "help" && cmd != "version" {
+		workflow, err := pebbles.LoadWorkflow(root)
+		if err != nil {
+			exitError(err)
+		}
+		activeWorkflow = workflow
+		issueTypes, err := pebbles.LoadIssueTypes(root)
+		if err != nil {
+			exitError(err)
+		}
+		activeIssueTypes = issueTypes
+		customFields, err := pebbles.LoadCustomFields(root)
+		if err != nil {
+			exitError(err)
+		}
+		activeFields = customFields
+	}
 	// Route to the subcommand handler.
 	switch cmd {
 	case "init":
 		runInit(root, args)
 	case "create":
-		runCreate(root, args)
+		runLocked(root, args, runCreate)
 	case "list":
 		runList(root, args)
 	case "show":
 		runShow(root, args)
+	case "search":
+		runSearch(root, args)
 	case "update":
-		runUpdate(root, args)
+		runLocked(root, args, runUpdate)
 	case "close":
-		runClose(root, args)
+		runLocked(root, args, runClose)
 	case "reopen":
-		runReopen(root, args)
+		runLocked(root, args, runReopen)
+	case "delete":
+		runLocked(root, args, runDelete)
+	case "undelete":
+		runLocked(root, args, runUndelete)
+	case "defer":
+		runLocked(root, args, runDefer)
+	case "undefer":
+		runLocked(root, args, runUndefer)
+	case "start":
+		runLocked(root, args, runStart)
+	case "stop":
+		runLocked(root, args, runStop)
+	case "log-time":
+		runLocked(root, args, runLogTime)
 	case "comment":
-		runComment(root, args)
+		runLocked(root, args, runComment)
 	case "import":
 		runImport(root, args)
 	case "dep":
-		runDep(root, args)
+		runLocked(root, args, runDep)
+	case "label":
+		runLocked(root, args, runLabel)
+	case "assign":
+		runLocked(root, args, runAssign)
+	case "unassign":
+		runLocked(root, args, runUnassign)
+	case "claim":
+		runLocked(root, args, runClaim)
+	case "release":
+		runLocked(root, args, runRelease)
+	case "dup":
+		runLocked(root, args, runDup)
 	case "ready":
 		runReady(root, args)
+	case "view":
+		runView(root, args)
 	case "prefix":
-		runPrefix(root, args)
+		runLocked(root, args, runPrefix)
 	case "rename":
-		runRename(root, args)
+		runLocked(root, args, runRename)
 	case "rename-prefix":
-		runRenamePrefix(root, args)
+		runLocked(root, args, runRenamePrefix)
 	case "log":
 		runLog(root, args)
+	case "doctor":
+		runDoctor(root, args)
+	case "resolve":
+		runResolve(root, args)
 	case "sync":
-		runSync(root, args)
+		runLocked(root, args, runSync)
 	case "self-update":
 		runSelfUpdate(root, args)
 	case "help":
@@ -82,6 +144,22 @@ func main() {
 	}
 }
 
+// runLocked runs a mutating command while holding the project lock so its
+// validation and appended events see a log no other pb process is changing.
+func runLocked(root string, args []string, run func(string, []string)) {
+	// Uninitialized projects have nowhere to lock; let the command report it.
+	if _, err := os.Stat(pebbles.PebblesDir(root)); err != nil {
+		run(root, args)
+		return
+	}
+	unlock, err := pebbles.LockProject(root)
+	if err != nil {
+		exitError(err)
+	}
+	defer unlock()
+	run(root, args)
+}
+
 // printVersion prints version metadata for the build.
 func printVersion() {
 	message := buildVersion
@@ -119,8 +197,12 @@ func runCreate(root string, args []string) {
 	setFlagUsage(fs, createHelp)
 	title := fs.String("title", "", "Issue title")
 	description := fs.String("description", "", "Issue description")
-	issueType := fs.String("type", "task", "Issue type")
-	priority := fs.String("priority", "P2", "Issue priority (P0-P4)")
+	issueType := fs.String("type", "", "Issue type")
+	priority := fs.String("priority", "", "Issue priority (P0-P4)")
+	due := fs.String("due", "", "Due date (YYYY-MM-DD, today, friday, +3d)")
+	estimate := fs.String("estimate", "", "Estimate in points (3) or time (4h)")
+	var fieldFlags repeatedString
+	fs.Var(&fieldFlags, "field", "Set a custom field (name=value, repeatable)")
 	_ = fs.Parse(args)
 	// Ensure the project is initialized and inputs are present.
 	if err := ensureProject(root); err != nil {
@@ -129,15 +211,38 @@ func runCreate(root string, args []string) {
 	if strings.TrimSpace(*title) == "" {
 		exitError(fmt.Errorf("title is required"))
 	}
-	// Load configuration and derive a deterministic issue ID.
-	cfg, err := pebbles.LoadConfig(root)
+	typeName, err := parseCreateType(*issueType)
 	if err != nil {
 		exitError(err)
 	}
-	parsedPriority, err := pebbles.ParsePriority(*priority)
+	fieldNames, fieldValues, err := parseFieldAssignments(fieldFlags)
 	if err != nil {
 		exitError(err)
 	}
+	dueAt, err := pebbles.ParseRelativeDate(*due, time.Now())
+	if err != nil {
+		exitError(err)
+	}
+	parsedEstimate, err := pebbles.ParseEstimate(*estimate)
+	if err != nil {
+		exitError(err)
+	}
+	if err := activeIssueTypes.CheckRequired(typeName, requiredFieldValues(*description, nil, fieldValues)); err != nil {
+		exitError(err)
+	}
+	// Load configuration and derive a deterministic issue ID.
+	cfg, err := pebbles.LoadConfig(root)
+	if err != nil {
+		exitError(err)
+	}
+	// Without --priority, the type's default priority applies.
+	parsedPriority := activeIssueTypes.DefaultPriority(typeName)
+	if strings.TrimSpace(*priority) != "" {
+		parsedPriority, err = pebbles.ParsePriority(*priority)
+		if err != nil {
+			exitError(err)
+		}
+	}
 	timestamp := pebbles.NowTimestamp()
 	issueID, err := pebbles.GenerateUniqueIssueID(
 		cfg.Prefix,
@@ -151,9 +256,20 @@ func runCreate(root string, args []string) {
 	if err != nil {
 		exitError(err)
 	}
-	event := pebbles.NewCreateEvent(issueID, *title, *description, *issueType, timestamp, parsedPriority)
+	events := []pebbles.Event{pebbles.NewCreateEvent(issueID, *title, *description, typeName, timestamp, parsedPriority)}
+	if dueAt != "" {
+		events = append(events, pebbles.NewDueEvent(issueID, dueAt, timestamp))
+	}
+	if parsedEstimate != "" {
+		events = append(events, pebbles.NewEstimateEvent(issueID, parsedEstimate, timestamp))
+	}
+	for _, name := range fieldNames {
+		if fieldValues[name] != "" {
+			events = append(events, pebbles.NewFieldSetEvent(issueID, name, fieldValues[name], timestamp))
+		}
+	}
 	// Append to the event log, then rebuild the cache for reads.
-	if err := pebbles.AppendEvent(root, event); err != nil {
+	if err := pebbles.AppendEvents(root, events); err != nil {
 		exitError(err)
 	}
 	if err := pebbles.RebuildCache(root); err != nil {
@@ -162,6 +278,51 @@ func runCreate(root string, args []string) {
 	fmt.Println(issueID)
 }
 
+// parseCreateType resolves the create --type flag. Without one, new issues
+// are tasks, or the first declared type when the project has no task type.
+func parseCreateType(input string) (string, error) {
+	if strings.TrimSpace(input) != "" {
+		return activeIssueTypes.ParseType(input)
+	}
+	if activeIssueTypes.Restricted() {
+		if _, ok := activeIssueTypes.Lookup("task"); !ok {
+			return activeIssueTypes.Names()[0], nil
+		}
+	}
+	return "task", nil
+}
+
+// parseFieldAssignments validates repeated --field name=value flags. It
+// returns the field names in flag order and the last value given for each.
+func parseFieldAssignments(inputs []string) ([]string, map[string]string, error) {
+	var names []string
+	values := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		name, value, err := activeFields.ParseAssignment(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, seen := values[name]; !seen {
+			names = append(names, name)
+		}
+		values[name] = value
+	}
+	return names, values, nil
+}
+
+// requiredFieldValues merges the description, current custom fields, and
+// pending field changes into the values CheckRequired inspects.
+func requiredFieldValues(description string, current, changes map[string]string) map[string]string {
+	values := map[string]string{pebbles.RequiredFieldDescription: description}
+	for name, value := range current {
+		values[name] = value
+	}
+	for name, value := range changes {
+		values[name] = value
+	}
+	return values
+}
+
 // runList handles pb list.
 func runList(root string, args []string) {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
@@ -174,37 +335,88 @@ func runList(root string, args []string) {
 	staleDays := fs.Int("stale-days", 30, "Days without activity to mark an issue stale")
 	jsonOut := fs.Bool("json", false, "Output JSON")
 	blocked := fs.Bool("blocked", false, "Show issues blocked by open dependencies")
+	label := fs.String("label", "", "Filter by label (comma-separated, all must match)")
+	noLabel := fs.Bool("no-label", false, "Show only issues without labels")
+	assignee := fs.String("assignee", "", "Filter by assignee (comma-separated)")
+	mine := fs.Bool("mine", false, "Show only issues assigned to the local actor")
+	resolution := fs.String("resolution", "", "Filter closed issues by resolution (comma-separated)")
+	var fieldFlags repeatedString
+	fs.Var(&fieldFlags, "field", "Filter by custom field (name=value, repeatable, all must match)")
+	overdue := fs.Bool("overdue", false, "Show open issues past their due date")
+	dueBefore := fs.String("due-before", "", "Show issues due on or before a date")
+	deferred := fs.Bool("deferred", false, "Show only deferred issues")
+	where := fs.String("where", "", "Filter with an expression (see pb list --help)")
+	sortSpec := fs.String("sort", "", "Sort by fields, e.g. priority,-updated (default: hierarchy order)")
+	limit := fs.Int("limit", 0, "Show at most N issues (0 = no limit)")
+	asOf := fs.String("as-of", "", "List issues as they were at a timestamp, date, or git ref")
 	_ = fs.Parse(args)
 	// Validate the project and requested filters before listing.
 	if err := ensureProject(root); err != nil {
 		exitError(err)
 	}
-	filters, err := parseListFilters(*status, *issueType, *priority)
+	filters, err := parseListFilters(*status, *issueType, *priority, *label, *noLabel)
+	if err != nil {
+		exitError(err)
+	}
+	order, err := parseListOrder(*sortSpec, *limit)
+	if err != nil {
+		exitError(err)
+	}
+	filters.assignees, err = parseAssigneeFilter(root, *assignee, *mine)
+	if err != nil {
+		exitError(err)
+	}
+	filters.resolutions, err = parseListResolutionFilter(*resolution)
+	if err != nil {
+		exitError(err)
+	}
+	if _, filters.fields, err = parseFieldAssignments(fieldFlags); err != nil {
+		exitError(err)
+	}
+	if len(filters.fields) == 0 {
+		filters.fields = nil
+	}
+	filters.overdue = *overdue
+	filters.deferred = *deferred
+	root = asOfRoot(root, *asOf)
+	defer snapshotCleanup()
+	whereStatus := false
+	filters.ids, whereStatus, err = parseWhereFilter(root, *where)
 	if err != nil {
 		exitError(err)
 	}
-	// By default, hide closed issues unless the user explicitly requested a
-	// status filter or asked to show everything.
-	if !*all && filters.statuses == nil {
-		filters.statuses = map[string]bool{
-			pebbles.StatusOpen:       true,
-			pebbles.StatusInProgress: true,
+	if strings.TrimSpace(*dueBefore) != "" {
+		filters.dueBefore, err = pebbles.ParseRelativeDate(*dueBefore, time.Now())
+		if err != nil {
+			exitError(err)
 		}
 	}
+	// By default, hide done issues unless the user explicitly requested a
+	// status or resolution filter (including in --where) or asked to show
+	// everything.
+	if !*all && filters.statuses == nil && filters.resolutions == nil && !whereStatus {
+		filters.hideDone = true
+	}
 	if *blocked {
 		blockedIssues, err := pebbles.ListBlockedIssues(root)
 		if err != nil {
 			exitError(err)
 		}
 		issues := make([]pebbles.Issue, 0, len(blockedIssues))
+		matched := make([]pebbles.BlockedIssue, 0, len(blockedIssues))
 		for _, item := range blockedIssues {
 			issues = append(issues, item.Issue)
+			if filters.matches(item.Issue) {
+				matched = append(matched, item)
+			}
+		}
+		if order.keys != nil {
+			sort.SliceStable(matched, func(i, j int) bool {
+				return pebbles.IssueLess(matched[i].Issue, matched[j].Issue, order.keys)
+			})
 		}
 		widths := issueColumnWidthsForIssues(issues)
-		for _, item := range blockedIssues {
-			if !filters.matches(item.Issue) {
-				continue
-			}
+		for _, item := range matched[:order.count(len(matched))] {
 			fmt.Println(formatBlockedIssueLine(item.Issue, item.Blockers, widths))
 		}
 		return
@@ -228,7 +440,7 @@ func runList(root string, args []string) {
 			if !filters.matches(item.Issue) {
 				continue
 			}
-			if item.Issue.Status == pebbles.StatusClosed {
+			if activeWorkflow.IsDone(item.Issue.Status) {
 				continue
 			}
 			lastActivity, err := issueLastActivity(item.Issue, activityByID)
@@ -243,19 +455,41 @@ func runList(root string, args []string) {
 				Activity: lastActivity.Format("2006-01-02"),
 			})
 		}
+		if order.keys != nil {
+			sort.SliceStable(rows, func(i, j int) bool {
+				return pebbles.IssueLess(rows[i].Item.Issue, rows[j].Item.Issue, order.keys)
+			})
+			for i := range rows {
+				rows[i].Item.Depth = 0
+			}
+		}
+		rows = rows[:order.count(len(rows))]
 		widths := staleIssueWidthsForRows(rows)
 		for _, row := range rows {
 			fmt.Println(formatStaleIssueLine(row, widths))
 		}
 		return
 	}
+	matched := make([]pebbles.IssueHierarchyItem, 0, len(issues))
+	for _, item := range issues {
+		if filters.matches(item.Issue) {
+			matched = append(matched, item)
+		}
+	}
+	// A sort replaces the hierarchy order, so the indentation goes too.
+	if order.keys != nil {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return pebbles.IssueLess(matched[i].Issue, matched[j].Issue, order.keys)
+		})
+		for i := range matched {
+			matched[i].Depth = 0
+		}
+	}
+	matched = matched[:order.count(len(matched))]
 	// JSON output skips column formatting and writes a single payload.
 	if *jsonOut {
-		entries := make([]issueJSON, 0, len(issues))
-		for _, item := range issues {
-			if !filters.matches(item.Issue) {
-				continue
-			}
+		entries := make([]issueJSON, 0, len(matched))
+		for _, item := range matched {
 			entry, err := issueJSONWithDeps(root, item.Issue)
 			if err != nil {
 				exitError(err)
@@ -268,10 +502,7 @@ func runList(root string, args []string) {
 		return
 	}
 	widths := issueColumnWidthsForHierarchy(issues)
-	for _, item := range issues {
-		if !filters.matches(item.Issue) {
-			continue
-		}
+	for _, item := range matched {
 		fmt.Println(formatIssueLine(item.Issue, item.Depth, widths))
 	}
 }
@@ -281,16 +512,24 @@ func runShow(root string, args []string) {
 	fs := flag.NewFlagSet("show", flag.ExitOnError)
 	setFlagUsage(fs, showHelp)
 	jsonOut := fs.Bool("json", false, "Output JSON")
-	_ = fs.Parse(args)
+	includeDeleted := fs.Bool("deleted", false, "Show the issue even if it is deleted")
+	asOf := fs.String("as-of", "", "Show the issue as it was at a timestamp, date, or git ref")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--as-of": true}))
 	if err := ensureProject(root); err != nil {
 		exitError(err)
 	}
+	root = asOfRoot(root, *asOf)
+	defer snapshotCleanup()
 	// Validate CLI arguments and load the issue state.
 	if fs.NArg() != 1 {
 		exitError(fmt.Errorf("show requires issue id"))
 	}
 	id := fs.Arg(0)
-	issue, deps, err := pebbles.GetIssue(root, id)
+	getIssue := pebbles.GetIssue
+	if *includeDeleted {
+		getIssue = pebbles.GetIssueIncludingDeleted
+	}
+	issue, deps, err := getIssue(root, id)
 	if err != nil {
 		exitError(err)
 	}
@@ -298,17 +537,21 @@ func runShow(root string, args []string) {
 	if err != nil {
 		exitError(err)
 	}
+	duplicates, err := pebbles.GetIssueDuplicates(root, issue.ID)
+	if err != nil {
+		exitError(err)
+	}
 	comments, err := pebbles.ListIssueComments(root, id)
 	if err != nil {
 		exitError(err)
 	}
 	if *jsonOut {
-		if err := printJSON(buildIssueDetailJSON(issue, deps, hierarchy, comments)); err != nil {
+		if err := printJSON(buildIssueDetailJSON(issue, deps, hierarchy, duplicates, comments)); err != nil {
 			exitError(err)
 		}
 		return
 	}
-	printIssue(root, issue, hierarchy, deps, comments)
+	printIssue(root, issue, hierarchy, duplicates, deps, comments)
 }
 
 // optionalString tracks whether a string flag was explicitly set.
@@ -332,6 +575,23 @@ func (opt *optionalString) Set(value string) error {
 	return nil
 }
 
+// repeatedString collects every value of a repeatable flag.
+type repeatedString []string
+
+// String returns the collected values for flag usage output.
+func (values *repeatedString) String() string {
+	if values == nil {
+		return ""
+	}
+	return strings.Join(*values, ", ")
+}
+
+// Set appends one flag value.
+func (values *repeatedString) Set(value string) error {
+	*values = append(*values, value)
+	return nil
+}
+
 // runUpdate handles pb update.
 func runUpdate(root string, args []string) {
 	fs := flag.NewFlagSet("update", flag.ExitOnError)
@@ -347,6 +607,12 @@ func runUpdate(root string, args []string) {
 	fs.Var(&description, "description", "New description")
 	fs.Var(&priority, "priority", "New priority (P0-P4)")
 	fs.Var(&parent, "parent", "Replace parent issue (use \"none\" to clear)")
+	var fieldFlags repeatedString
+	fs.Var(&fieldFlags, "field", "Set a custom field (name=value, repeatable; empty value clears)")
+	var due optionalString
+	fs.Var(&due, "due", "Due date (YYYY-MM-DD, today, friday, +3d, none)")
+	var estimate optionalString
+	fs.Var(&estimate, "estimate", "Estimate in points (3) or time (4h); none clears")
 	// Support `pb update <id> --status ...` by moving the id to the end.
 	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
 		args = append(args[1:], args[0])
@@ -359,7 +625,7 @@ func runUpdate(root string, args []string) {
 	if fs.NArg() != 1 {
 		exitError(fmt.Errorf("update requires issue id"))
 	}
-	if strings.TrimSpace(*status) == "" && !title.set && !issueType.set && !description.set && !priority.set && !parent.set {
+	if strings.TrimSpace(*status) == "" && !title.set && !issueType.set && !description.set && !priority.set && !parent.set && len(fieldFlags) == 0 && !due.set && !estimate.set {
 		exitError(fmt.Errorf("at least one field is required"))
 	}
 	if title.set && strings.TrimSpace(title.value) == "" {
@@ -381,14 +647,43 @@ func runUpdate(root string, args []string) {
 	timestamp := pebbles.NowTimestamp()
 	var events []pebbles.Event
 	if strings.TrimSpace(*status) != "" {
-		events = append(events, pebbles.NewStatusEvent(id, *status, timestamp))
+		// Statuses must be declared and reachable from the current one.
+		newStatus, err := activeWorkflow.ParseStatus(*status)
+		if err != nil {
+			exitError(err)
+		}
+		if err := activeWorkflow.CheckTransition(issue.Status, newStatus); err != nil {
+			exitError(fmt.Errorf("%s: %w", id, err))
+		}
+		events = append(events, pebbles.NewStatusEventWithCategory(id, newStatus, activeWorkflow.Category(newStatus), timestamp))
 	}
 	if title.set {
 		events = append(events, pebbles.NewTitleUpdatedEvent(id, title.value, timestamp))
 	}
+	// Check the type and its required fields against the resulting issue.
+	newType := issue.IssueType
+	if issueType.set {
+		newType, err = activeIssueTypes.ParseType(issueType.value)
+		if err != nil {
+			exitError(err)
+		}
+	}
+	newDescription := issue.Description
+	if description.set {
+		newDescription = description.value
+	}
+	fieldNames, fieldValues, err := parseFieldAssignments(fieldFlags)
+	if err != nil {
+		exitError(err)
+	}
+	if issueType.set || description.set || len(fieldNames) > 0 {
+		if err := activeIssueTypes.CheckRequired(newType, requiredFieldValues(newDescription, issue.Fields, fieldValues)); err != nil {
+			exitError(fmt.Errorf("%s: %w", id, err))
+		}
+	}
 	updatePayload := make(map[string]string)
 	if issueType.set {
-		updatePayload["type"] = issueType.value
+		updatePayload["type"] = newType
 	}
 	if description.set {
 		updatePayload["description"] = description.value
@@ -403,6 +698,23 @@ func runUpdate(root string, args []string) {
 	if len(updatePayload) > 0 {
 		events = append(events, pebbles.NewUpdateEvent(id, timestamp, updatePayload))
 	}
+	for _, name := range fieldNames {
+		events = append(events, pebbles.NewFieldSetEvent(id, name, fieldValues[name], timestamp))
+	}
+	if due.set {
+		dueAt, err := pebbles.ParseRelativeDate(due.value, time.Now())
+		if err != nil {
+			exitError(err)
+		}
+		events = append(events, pebbles.NewDueEvent(id, dueAt, timestamp))
+	}
+	if estimate.set {
+		parsedEstimate, err := pebbles.ParseEstimate(estimate.value)
+		if err != nil {
+			exitError(err)
+		}
+		events = append(events, pebbles.NewEstimateEvent(id, parsedEstimate, timestamp))
+	}
 	if parent.set {
 		trimmedParent := strings.TrimSpace(parent.value)
 		clearParent := trimmedParent == "" || strings.EqualFold(trimmedParent, "none")
@@ -435,10 +747,8 @@ func runUpdate(root string, args []string) {
 			events = append(events, pebbles.NewDepAddEvent(childID, parentIssue.ID, pebbles.DepTypeParentChild, timestamp))
 		}
 	}
-	for _, event := range events {
-		if err := pebbles.AppendEvent(root, event); err != nil {
-			exitError(err)
-		}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
 	}
 	if err := pebbles.RebuildCache(root); err != nil {
 		exitError(err)
@@ -449,7 +759,9 @@ func runUpdate(root string, args []string) {
 func runClose(root string, args []string) {
 	fs := flag.NewFlagSet("close", flag.ExitOnError)
 	setFlagUsage(fs, closeHelp)
-	_ = fs.Parse(args)
+	reason := fs.String("reason", "", "Why the issue was closed")
+	resolutionInput := fs.String("resolution", pebbles.ResolutionDone, "Resolution (done, wontfix, duplicate, obsolete)")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--reason": true, "--resolution": true}))
 	// Validate inputs before closing the issues.
 	if err := ensureProject(root); err != nil {
 		exitError(err)
@@ -457,29 +769,307 @@ func runClose(root string, args []string) {
 	if fs.NArg() < 1 {
 		exitError(fmt.Errorf("close requires at least one issue id"))
 	}
-	// Validate all issues exist before writing any events.
+	resolution, err := pebbles.ParseResolution(*resolutionInput)
+	if err != nil {
+		exitError(err)
+	}
+	if resolution == "" {
+		exitError(fmt.Errorf("resolution is required"))
+	}
+	// Validate all issues exist and may close before writing any events.
 	ids := make([]string, fs.NArg())
 	for i := 0; i < fs.NArg(); i++ {
 		issue, _, err := pebbles.GetIssue(root, fs.Arg(i))
 		if err != nil {
 			exitError(err)
 		}
+		if err := activeWorkflow.CheckTransition(issue.Status, pebbles.StatusClosed); err != nil {
+			exitError(fmt.Errorf("%s: %w", issue.ID, err))
+		}
 		ids[i] = issue.ID
 	}
 	// Append close events for each issue.
 	timestamp := pebbles.NowTimestamp()
+	events := make([]pebbles.Event, 0, len(ids))
 	for _, id := range ids {
-		event := pebbles.NewCloseEvent(id, timestamp)
-		if err := pebbles.AppendEvent(root, event); err != nil {
+		events = append(events, pebbles.NewResolvedCloseEvent(id, resolution, strings.TrimSpace(*reason), timestamp))
+	}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
+	}
+	// Rebuild the cache once after all events are written.
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runDup handles pb dup.
+func runDup(root string, args []string) {
+	fs := flag.NewFlagSet("dup", flag.ExitOnError)
+	setFlagUsage(fs, dupHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 2 {
+		exitError(fmt.Errorf("usage: pb dup <issue> <canonical-issue>"))
+	}
+	issue, _, err := pebbles.GetIssue(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	existing, err := pebbles.GetIssueDuplicates(root, issue.ID)
+	if err != nil {
+		exitError(err)
+	}
+	if existing.Canonical != nil {
+		exitError(fmt.Errorf("%s is already a duplicate of %s", issue.ID, existing.Canonical.ID))
+	}
+	if err := activeWorkflow.CheckTransition(issue.Status, pebbles.StatusClosed); err != nil {
+		exitError(fmt.Errorf("%s: %w", issue.ID, err))
+	}
+	// Link to the surviving issue when the target is itself a duplicate.
+	canonical, _, err := pebbles.GetIssue(root, fs.Arg(1))
+	if err != nil {
+		exitError(err)
+	}
+	target, err := pebbles.GetIssueDuplicates(root, canonical.ID)
+	if err != nil {
+		exitError(err)
+	}
+	if target.Canonical != nil {
+		canonical = *target.Canonical
+	}
+	if canonical.ID == issue.ID {
+		exitError(fmt.Errorf("an issue cannot be a duplicate of itself"))
+	}
+	timestamp := pebbles.NowTimestamp()
+	events := []pebbles.Event{
+		pebbles.NewDepAddEvent(issue.ID, canonical.ID, pebbles.DepTypeDuplicateOf, timestamp),
+		pebbles.NewResolvedCloseEvent(issue.ID, pebbles.ResolutionDuplicate, fmt.Sprintf("Duplicate of %s", canonical.ID), timestamp),
+	}
+	// Append the link and close together, then rebuild the cache once.
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runDelete handles pb delete.
+func runDelete(root string, args []string) {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	setFlagUsage(fs, deleteHelp)
+	reason := fs.String("reason", "", "Why the issue was deleted")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--reason": true}))
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() < 1 {
+		exitError(fmt.Errorf("delete requires at least one issue id"))
+	}
+	// Validate all issues exist and are live before writing any events.
+	ids := make([]string, fs.NArg())
+	for i := 0; i < fs.NArg(); i++ {
+		issue, _, err := pebbles.GetIssue(root, fs.Arg(i))
+		if err != nil {
 			exitError(err)
 		}
+		ids[i] = issue.ID
+	}
+	timestamp := pebbles.NowTimestamp()
+	events := make([]pebbles.Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, pebbles.NewDeleteEvent(id, strings.TrimSpace(*reason), timestamp))
+	}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runUndelete handles pb undelete.
+func runUndelete(root string, args []string) {
+	fs := flag.NewFlagSet("undelete", flag.ExitOnError)
+	setFlagUsage(fs, undeleteHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("undelete requires issue id"))
+	}
+	issue, _, err := pebbles.GetIssueIncludingDeleted(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	if issue.DeletedAt == "" {
+		exitError(fmt.Errorf("issue %s is not deleted", issue.ID))
+	}
+	if err := pebbles.AppendEvent(root, pebbles.NewUndeleteEvent(issue.ID, pebbles.NowTimestamp())); err != nil {
+		exitError(err)
 	}
-	// Rebuild the cache once after all events are written.
 	if err := pebbles.RebuildCache(root); err != nil {
 		exitError(err)
 	}
 }
 
+// runDefer handles pb defer.
+func runDefer(root string, args []string) {
+	fs := flag.NewFlagSet("defer", flag.ExitOnError)
+	setFlagUsage(fs, deferHelp)
+	until := fs.String("until", "", "Date the issue becomes ready again")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--until": true}))
+	// Validate inputs before deferring the issue.
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("defer requires issue id"))
+	}
+	if strings.TrimSpace(*until) == "" {
+		exitError(fmt.Errorf("defer requires --until"))
+	}
+	now := time.Now()
+	date, err := pebbles.ParseRelativeDate(*until, now)
+	if err != nil {
+		exitError(err)
+	}
+	if days, _ := pebbles.DueInDays(date, now); date == "" || days <= 0 {
+		exitError(fmt.Errorf("--until must be a date after today"))
+	}
+	issue, _, err := pebbles.GetIssue(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	if activeWorkflow.IsDone(issue.Status) {
+		exitError(fmt.Errorf("issue %s is %s; reopen it before deferring", issue.ID, issue.Status))
+	}
+	if err := pebbles.AppendEvent(root, pebbles.NewDeferEvent(issue.ID, date, pebbles.NowTimestamp())); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runUndefer handles pb undefer.
+func runUndefer(root string, args []string) {
+	fs := flag.NewFlagSet("undefer", flag.ExitOnError)
+	setFlagUsage(fs, undeferHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("undefer requires issue id"))
+	}
+	issue, _, err := pebbles.GetIssue(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	if !issue.DeferActive(time.Now()) {
+		exitError(fmt.Errorf("issue %s is not deferred", issue.ID))
+	}
+	if err := pebbles.AppendEvent(root, pebbles.NewUndeferEvent(issue.ID, pebbles.NowTimestamp())); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runStart handles pb start.
+func runStart(root string, args []string) {
+	fs := flag.NewFlagSet("start", flag.ExitOnError)
+	setFlagUsage(fs, startHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("start requires issue id"))
+	}
+	issue, _, err := pebbles.GetIssue(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	if issue.TimerStartedAt != "" {
+		exitError(fmt.Errorf("issue %s already has a timer running since %s", issue.ID, formatDate(issue.TimerStartedAt)))
+	}
+	if activeWorkflow.IsDone(issue.Status) {
+		exitError(fmt.Errorf("issue %s is %s; reopen it before starting a timer", issue.ID, issue.Status))
+	}
+	if err := pebbles.AppendEvent(root, pebbles.NewTimerStartEvent(issue.ID, pebbles.NowTimestamp())); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runStop handles pb stop.
+func runStop(root string, args []string) {
+	fs := flag.NewFlagSet("stop", flag.ExitOnError)
+	setFlagUsage(fs, stopHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("stop requires issue id"))
+	}
+	issue, _, err := pebbles.GetIssue(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	if issue.TimerStartedAt == "" {
+		exitError(fmt.Errorf("issue %s has no running timer (see pb start)", issue.ID))
+	}
+	minutes, err := pebbles.TimerMinutes(issue.TimerStartedAt, time.Now())
+	if err != nil {
+		exitError(err)
+	}
+	appendWorkLog(root, issue, pebbles.NewWorkLogEvent(issue.ID, minutes, issue.TimerStartedAt, pebbles.NowTimestamp()), minutes)
+}
+
+// runLogTime handles pb log-time.
+func runLogTime(root string, args []string) {
+	fs := flag.NewFlagSet("log-time", flag.ExitOnError)
+	setFlagUsage(fs, logTimeHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 2 {
+		exitError(fmt.Errorf("log-time requires issue id and duration"))
+	}
+	minutes, err := pebbles.ParseWorkDuration(fs.Arg(1))
+	if err != nil {
+		exitError(err)
+	}
+	issue, _, err := pebbles.GetIssue(root, fs.Arg(0))
+	if err != nil {
+		exitError(err)
+	}
+	appendWorkLog(root, issue, pebbles.NewWorkLogEvent(issue.ID, minutes, "", pebbles.NowTimestamp()), minutes)
+}
+
+// appendWorkLog writes a work_log event and reports the issue's new total.
+func appendWorkLog(root string, issue pebbles.Issue, event pebbles.Event, minutes int) {
+	if err := pebbles.AppendEvent(root, event); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+	total := pebbles.FormatMinutes(issue.LoggedMinutes + minutes)
+	fmt.Printf("Logged %s on %s (total %s)\n", pebbles.FormatMinutes(minutes), issue.ID, total)
+}
+
 // runReopen handles pb reopen.
 func runReopen(root string, args []string) {
 	fs := flag.NewFlagSet("reopen", flag.ExitOnError)
@@ -497,10 +1087,13 @@ func runReopen(root string, args []string) {
 	if err != nil {
 		exitError(err)
 	}
-	if issue.Status != pebbles.StatusClosed {
+	if !activeWorkflow.IsDone(issue.Status) {
 		exitError(fmt.Errorf("issue is already open"))
 	}
-	event := pebbles.NewStatusEvent(id, pebbles.StatusOpen, pebbles.NowTimestamp())
+	if err := activeWorkflow.CheckTransition(issue.Status, pebbles.StatusOpen); err != nil {
+		exitError(fmt.Errorf("%s: %w", issue.ID, err))
+	}
+	event := pebbles.NewStatusEventWithCategory(id, pebbles.StatusOpen, activeWorkflow.Category(pebbles.StatusOpen), pebbles.NowTimestamp())
 	// Append the status event and rebuild the cache.
 	if err := pebbles.AppendEvent(root, event); err != nil {
 		exitError(err)
@@ -510,8 +1103,18 @@ func runReopen(root string, args []string) {
 	}
 }
 
-// runComment handles pb comment.
+// runComment handles pb comment, routing edit and rm to their handlers.
 func runComment(root string, args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "edit":
+			runCommentEdit(root, args[1:])
+			return
+		case "rm":
+			runCommentRemove(root, args[1:])
+			return
+		}
+	}
 	fs := flag.NewFlagSet("comment", flag.ExitOnError)
 	setFlagUsage(fs, commentHelp)
 	body := fs.String("body", "", "Comment body")
@@ -539,6 +1142,68 @@ func runComment(root string, args []string) {
 	if err := pebbles.RebuildCache(root); err != nil {
 		exitError(err)
 	}
+	fmt.Println(pebbles.CommentID(event))
+}
+
+// runCommentEdit handles pb comment edit.
+func runCommentEdit(root string, args []string) {
+	fs := flag.NewFlagSet("comment edit", flag.ExitOnError)
+	setFlagUsage(fs, commentHelp)
+	body := fs.String("body", "", "New comment body")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--body": true}))
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("usage: pb comment edit <comment-id> --body <text>"))
+	}
+	if strings.TrimSpace(*body) == "" {
+		exitError(fmt.Errorf("comment body is required"))
+	}
+	comment := loadEditableComment(root, fs.Arg(0))
+	if comment.Body == *body {
+		return
+	}
+	event := pebbles.NewCommentEditEvent(comment.IssueID, comment.ID, *body, pebbles.NowTimestamp())
+	if err := pebbles.AppendEvent(root, event); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runCommentRemove handles pb comment rm.
+func runCommentRemove(root string, args []string) {
+	fs := flag.NewFlagSet("comment rm", flag.ExitOnError)
+	setFlagUsage(fs, commentHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("usage: pb comment rm <comment-id>"))
+	}
+	comment := loadEditableComment(root, fs.Arg(0))
+	event := pebbles.NewCommentDeleteEvent(comment.IssueID, comment.ID, pebbles.NowTimestamp())
+	if err := pebbles.AppendEvent(root, event); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// loadEditableComment fetches a live comment on a live issue or exits.
+func loadEditableComment(root, commentID string) pebbles.IssueComment {
+	comment, err := pebbles.GetComment(root, commentID)
+	if err != nil {
+		exitError(err)
+	}
+	if _, _, err := pebbles.GetIssue(root, comment.IssueID); err != nil {
+		exitError(err)
+	}
+	return comment
 }
 
 // runImport handles pb import.
@@ -586,6 +1251,7 @@ func runImportBeads(root string, args []string) {
 		Prefix:            *prefix,
 		IncludeTombstones: *includeTombstones,
 		Now:               time.Now,
+		IssueTypes:        activeIssueTypes,
 	})
 	if err != nil {
 		exitError(err)
@@ -644,10 +1310,16 @@ func runDep(root string, args []string) {
 			fmt.Print(depTreeHelp)
 			return
 		}
-		if len(args) != 2 {
-			exitError(fmt.Errorf("usage: pb dep tree <issue>"))
+		treeFlags := flag.NewFlagSet("dep tree", flag.ExitOnError)
+		setFlagUsage(treeFlags, depTreeHelp)
+		asOf := treeFlags.String("as-of", "", "Show the tree as it was at a timestamp, date, or git ref")
+		_ = treeFlags.Parse(reorderFlags(args[1:], map[string]bool{"--as-of": true}))
+		if treeFlags.NArg() != 1 {
+			exitError(fmt.Errorf("usage: pb dep tree [--as-of <when>] <issue>"))
 		}
-		runDepTree(root, args[1])
+		root = asOfRoot(root, *asOf)
+		defer snapshotCleanup()
+		runDepTree(root, treeFlags.Arg(0))
 	default:
 		exitError(fmt.Errorf("usage: pb dep <add|rm|tree> [args]"))
 	}
@@ -664,41 +1336,183 @@ func runDepAdd(root, issueID, dependsOn, depType string) {
 	if err != nil {
 		exitError(err)
 	}
-	issueID = issue.ID
-	dependsOn = parent.ID
+	issueID = issue.ID
+	dependsOn = parent.ID
+	var events []pebbles.Event
+	// Parent-child deps should use parent-based child IDs for lineage.
+	if depType == pebbles.DepTypeParentChild && !pebbles.HasParentChildSuffix(dependsOn, issueID) {
+		childID, err := pebbles.NextChildIssueID(root, dependsOn)
+		if err != nil {
+			exitError(err)
+		}
+		rename := pebbles.NewRenameEvent(issueID, childID, pebbles.NowTimestamp())
+		events = append(events, rename)
+		issueID = childID
+	}
+	events = append(events, pebbles.NewDepAddEvent(issueID, dependsOn, depType, pebbles.NowTimestamp()))
+	// Append the events and rebuild the cache once.
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runDepRemove appends a dependency removal event.
+func runDepRemove(root, issueID, dependsOn, depType string) {
+	// Ensure both sides exist before appending the event.
+	if _, _, err := pebbles.GetIssue(root, issueID); err != nil {
+		exitError(err)
+	}
+	if _, _, err := pebbles.GetIssue(root, dependsOn); err != nil {
+		exitError(err)
+	}
+	event := pebbles.NewDepRemoveEvent(issueID, dependsOn, depType, pebbles.NowTimestamp())
+	// Append the event and rebuild the cache.
+	if err := pebbles.AppendEvent(root, event); err != nil {
+		exitError(err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		exitError(err)
+	}
+}
+
+// runDepTree prints a dependency tree for an issue.
+func runDepTree(root, issueID string) {
+	issue, _, err := pebbles.GetIssue(root, issueID)
+	if err != nil {
+		exitError(err)
+	}
+	targetID := issue.ID
+	depType := pebbles.DepTypeBlocks
+	hasHierarchy, err := pebbles.HasParentChildRelations(root, issueID)
+	if err != nil {
+		exitError(err)
+	}
+	if hasHierarchy {
+		depType = pebbles.DepTypeParentChild
+		node, err := pebbles.ParentChildTree(root, issueID)
+		if err != nil {
+			exitError(err)
+		}
+		fmt.Printf("TREE (%s)\n", depType)
+		printDepTree(node, 0, targetID)
+		return
+	}
+	node, err := pebbles.DependencyTree(root, issueID)
+	if err != nil {
+		exitError(err)
+	}
+	fmt.Printf("TREE (%s)\n", depType)
+	printDepTree(node, 0, targetID)
+}
+
+// runLabel handles pb label commands.
+func runLabel(root string, args []string) {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		fmt.Print(labelHelp)
+		return
+	}
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	// Route subcommands for label operations.
+	action := args[0]
+	switch action {
+	case "add", "rm":
+		if len(args) == 2 && isHelpArg(args[1]) {
+			fmt.Print(labelHelp)
+			return
+		}
+		if len(args) < 3 {
+			exitError(fmt.Errorf("usage: pb label %s <issue> <label> [label...]", action))
+		}
+		runLabelChange(root, action == "add", args[1], args[2:])
+	default:
+		exitError(fmt.Errorf("usage: pb label <add|rm> <issue> <label> [label...]"))
+	}
+}
+
+// runLabelChange appends label events for labels that change the issue.
+func runLabelChange(root string, add bool, issueID string, inputs []string) {
+	issue, _, err := pebbles.GetIssue(root, issueID)
+	if err != nil {
+		exitError(err)
+	}
+	// Normalize every label first so a bad label appends nothing.
 	var events []pebbles.Event
-	// Parent-child deps should use parent-based child IDs for lineage.
-	if depType == pebbles.DepTypeParentChild && !pebbles.HasParentChildSuffix(dependsOn, issueID) {
-		childID, err := pebbles.NextChildIssueID(root, dependsOn)
+	seen := make(map[string]bool)
+	for _, input := range inputs {
+		label, err := pebbles.NormalizeLabel(input)
 		if err != nil {
 			exitError(err)
 		}
-		rename := pebbles.NewRenameEvent(issueID, childID, pebbles.NowTimestamp())
-		events = append(events, rename)
-		issueID = childID
+		// Skip repeats and labels that are already in the requested state.
+		if seen[label] || issue.HasLabel(label) == add {
+			continue
+		}
+		seen[label] = true
+		if add {
+			events = append(events, pebbles.NewLabelAddEvent(issue.ID, label, pebbles.NowTimestamp()))
+		} else {
+			events = append(events, pebbles.NewLabelRemoveEvent(issue.ID, label, pebbles.NowTimestamp()))
+		}
+	}
+	if len(events) == 0 {
+		return
 	}
-	events = append(events, pebbles.NewDepAddEvent(issueID, dependsOn, depType, pebbles.NowTimestamp()))
 	// Append the events and rebuild the cache once.
-	for _, event := range events {
-		if err := pebbles.AppendEvent(root, event); err != nil {
-			exitError(err)
-		}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
 	}
 	if err := pebbles.RebuildCache(root); err != nil {
 		exitError(err)
 	}
 }
 
-// runDepRemove appends a dependency removal event.
-func runDepRemove(root, issueID, dependsOn, depType string) {
-	// Ensure both sides exist before appending the event.
-	if _, _, err := pebbles.GetIssue(root, issueID); err != nil {
+// runAssign handles pb assign.
+func runAssign(root string, args []string) {
+	fs := flag.NewFlagSet("assign", flag.ExitOnError)
+	setFlagUsage(fs, assignHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
 		exitError(err)
 	}
-	if _, _, err := pebbles.GetIssue(root, dependsOn); err != nil {
+	if fs.NArg() != 2 {
+		exitError(fmt.Errorf("usage: pb assign <issue> <assignee>"))
+	}
+	assignee := strings.TrimSpace(fs.Arg(1))
+	if assignee == "" {
+		exitError(fmt.Errorf("assignee is required"))
+	}
+	appendAssignEvent(root, fs.Arg(0), assignee)
+}
+
+// runUnassign handles pb unassign.
+func runUnassign(root string, args []string) {
+	fs := flag.NewFlagSet("unassign", flag.ExitOnError)
+	setFlagUsage(fs, unassignHelp)
+	_ = fs.Parse(args)
+	if err := ensureProject(root); err != nil {
 		exitError(err)
 	}
-	event := pebbles.NewDepRemoveEvent(issueID, dependsOn, depType, pebbles.NowTimestamp())
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("usage: pb unassign <issue>"))
+	}
+	appendAssignEvent(root, fs.Arg(0), "")
+}
+
+// appendAssignEvent records an assignee change unless it is already in effect.
+func appendAssignEvent(root, issueID, assignee string) {
+	issue, _, err := pebbles.GetIssue(root, issueID)
+	if err != nil {
+		exitError(err)
+	}
+	if issue.Assignee == assignee {
+		return
+	}
+	event := pebbles.NewAssignEvent(issue.ID, assignee, pebbles.NowTimestamp())
 	// Append the event and rebuild the cache.
 	if err := pebbles.AppendEvent(root, event); err != nil {
 		exitError(err)
@@ -708,34 +1522,41 @@ func runDepRemove(root, issueID, dependsOn, depType string) {
 	}
 }
 
-// runDepTree prints a dependency tree for an issue.
-func runDepTree(root, issueID string) {
-	issue, _, err := pebbles.GetIssue(root, issueID)
-	if err != nil {
+// runClaim handles pb claim.
+func runClaim(root string, args []string) {
+	fs := flag.NewFlagSet("claim", flag.ExitOnError)
+	setFlagUsage(fs, claimHelp)
+	lease := fs.Duration("lease", pebbles.DefaultClaimLease, "How long the claim lasts")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--lease": true}))
+	if err := ensureProject(root); err != nil {
 		exitError(err)
 	}
-	targetID := issue.ID
-	depType := pebbles.DepTypeBlocks
-	hasHierarchy, err := pebbles.HasParentChildRelations(root, issueID)
+	if fs.NArg() > 1 {
+		exitError(fmt.Errorf("usage: pb claim [<issue>] [--lease <duration>]"))
+	}
+	issue, err := pebbles.ClaimIssue(root, fs.Arg(0), pebbles.ResolveActor(root), *lease)
 	if err != nil {
 		exitError(err)
 	}
-	if hasHierarchy {
-		depType = pebbles.DepTypeParentChild
-		node, err := pebbles.ParentChildTree(root, issueID)
-		if err != nil {
-			exitError(err)
-		}
-		fmt.Printf("TREE (%s)\n", depType)
-		printDepTree(node, 0, targetID)
-		return
+	// Print only the ID so agents can capture it.
+	fmt.Println(issue.ID)
+}
+
+// runRelease handles pb release.
+func runRelease(root string, args []string) {
+	fs := flag.NewFlagSet("release", flag.ExitOnError)
+	setFlagUsage(fs, releaseHelp)
+	force := fs.Bool("force", false, "Release a claim held by another actor")
+	_ = fs.Parse(reorderFlags(args, nil))
+	if err := ensureProject(root); err != nil {
+		exitError(err)
 	}
-	node, err := pebbles.DependencyTree(root, issueID)
-	if err != nil {
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("usage: pb release <issue> [--force]"))
+	}
+	if err := pebbles.ReleaseIssue(root, fs.Arg(0), pebbles.ResolveActor(root), *force); err != nil {
 		exitError(err)
 	}
-	fmt.Printf("TREE (%s)\n", depType)
-	printDepTree(node, 0, targetID)
 }
 
 // runReady handles pb ready.
@@ -743,14 +1564,45 @@ func runReady(root string, args []string) {
 	fs := flag.NewFlagSet("ready", flag.ExitOnError)
 	setFlagUsage(fs, readyHelp)
 	jsonOut := fs.Bool("json", false, "Output JSON")
+	assignee := fs.String("assignee", "", "Filter by assignee (comma-separated)")
+	mine := fs.Bool("mine", false, "Show only issues assigned to the local actor")
+	where := fs.String("where", "", "Filter with an expression (see pb list --help)")
+	sortSpec := fs.String("sort", "", "Sort by fields, e.g. priority,-updated (default: work order)")
+	limit := fs.Int("limit", 0, "Show at most N issues (0 = no limit)")
+	asOf := fs.String("as-of", "", "Show ready work as it was at a timestamp, date, or git ref")
 	_ = fs.Parse(args)
 	if err := ensureProject(root); err != nil {
 		exitError(err)
 	}
-	issues, err := pebbles.ListReadyIssues(root)
+	assignees, err := parseAssigneeFilter(root, *assignee, *mine)
+	if err != nil {
+		exitError(err)
+	}
+	root = asOfRoot(root, *asOf)
+	defer snapshotCleanup()
+	order, err := parseListOrder(*sortSpec, *limit)
+	if err != nil {
+		exitError(err)
+	}
+	filters := listFilters{assignees: assignees}
+	if filters.ids, _, err = parseWhereFilter(root, *where); err != nil {
+		exitError(err)
+	}
+	ready, err := pebbles.ListReadyIssues(root)
 	if err != nil {
 		exitError(err)
 	}
+	issues := make([]pebbles.Issue, 0, len(ready))
+	for _, issue := range ready {
+		if filters.matches(issue) {
+			issues = append(issues, issue)
+		}
+	}
+	// Without --sort, keep the work order from ListReadyIssues.
+	if order.keys != nil {
+		pebbles.SortIssues(issues, order.keys)
+	}
+	issues = issues[:order.count(len(issues))]
 	if *jsonOut {
 		entries := make([]issueJSON, 0, len(issues))
 		for _, issue := range issues {
@@ -880,7 +1732,7 @@ func runRenamePrefix(root string, args []string) {
 	events := make([]pebbles.Event, 0)
 	seen := make(map[string]bool)
 	for _, issue := range issues {
-		if *open && issue.Status == pebbles.StatusClosed {
+		if *open && activeWorkflow.IsDone(issue.Status) {
 			continue
 		}
 		prefix, suffix, ok := splitIssueID(issue.ID)
@@ -905,10 +1757,8 @@ func runRenamePrefix(root string, args []string) {
 		events = append(events, pebbles.NewRenameEvent(issue.ID, targetID, pebbles.NowTimestamp()))
 	}
 	// Append all rename events in order, then rebuild the cache once.
-	for _, event := range events {
-		if err := pebbles.AppendEvent(root, event); err != nil {
-			exitError(err)
-		}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		exitError(err)
 	}
 	if err := pebbles.RebuildCache(root); err != nil {
 		exitError(err)
@@ -967,14 +1817,48 @@ func runSync(root string, args []string) {
 	}
 }
 
-// ensureProject checks that the .pebbles directory exists.
+// ensureProject checks that the .pebbles directory exists and warns about
+// events this version of pb skipped while building the cache.
 func ensureProject(root string) error {
 	if _, err := os.Stat(pebbles.EventsPath(root)); err != nil {
 		return fmt.Errorf("pebbles not initialized; run pb init")
 	}
+	warnSkippedEvents(root)
 	return nil
 }
 
+// warnSkippedEvents prints a stderr warning when replay skipped events.
+// Cache errors are left for the command itself to report.
+func warnSkippedEvents(root string) {
+	skipped, err := pebbles.ListSkippedEvents(root)
+	if err != nil || len(skipped) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", formatSkippedWarning(skipped))
+}
+
+// formatSkippedWarning summarizes skipped events by type.
+func formatSkippedWarning(skipped []pebbles.SkippedEvent) string {
+	counts := make(map[string]int)
+	var types []string
+	for _, event := range skipped {
+		if counts[event.Type] == 0 {
+			types = append(types, event.Type)
+		}
+		counts[event.Type]++
+	}
+	sort.Strings(types)
+	parts := make([]string, 0, len(types))
+	for _, eventType := range types {
+		parts = append(parts, fmt.Sprintf("%s x%d", eventType, counts[eventType]))
+	}
+	return fmt.Sprintf(
+		"skipped %d events this version of pb cannot apply (%s); see pb log or upgrade pb",
+		len(skipped),
+		strings.Join(parts, ", "),
+	)
+}
+
 func resolveImportRoot(root, from string) (string, error) {
 	trimmed := strings.TrimSpace(from)
 	if trimmed == "" {
@@ -1057,7 +1941,7 @@ func printBeadsImportSummary(result pebbles.BeadsImportResult, dryRun bool, targ
 }
 
 // printIssue renders a single issue to stdout.
-func printIssue(root string, issue pebbles.Issue, hierarchy pebbles.IssueHierarchy, deps []string, comments []pebbles.IssueComment) {
+func printIssue(root string, issue pebbles.Issue, hierarchy pebbles.IssueHierarchy, duplicates pebbles.IssueDuplicates, deps []string, comments []pebbles.IssueComment) {
 	// Header includes the status icon and priority badge.
 	statusIcon := renderStatusIcon(issue.Status)
 	priorityLabel := renderPriorityLabel(issue.Priority)
@@ -1073,6 +1957,41 @@ func printIssue(root string, issue pebbles.Issue, hierarchy pebbles.IssueHierarc
 	fmt.Println(header)
 	// Core metadata block.
 	fmt.Printf("Type: %s\n", renderIssueType(issue.IssueType))
+	if duplicates.Canonical != nil {
+		fmt.Printf("Duplicate of: %s (%s) - %s\n", duplicates.Canonical.ID, duplicates.Canonical.Status, duplicates.Canonical.Title)
+	}
+	if issue.Assignee != "" {
+		fmt.Printf("Assignee: %s\n", issue.Assignee)
+	}
+	if issue.ClaimedBy != "" {
+		fmt.Printf("Claimed: %s\n", formatClaim(issue, time.Now().UTC()))
+	}
+	if issue.DueAt != "" {
+		fmt.Printf("Due: %s\n", formatDueLine(issue, time.Now()))
+	}
+	if issue.DeferActive(time.Now()) {
+		fmt.Printf("Deferred: until %s\n", issue.DeferredUntil)
+	}
+	if issue.Estimate != "" || issue.LoggedMinutes > 0 {
+		fmt.Printf("Time: %s\n", formatTimeLine(issue.LoggedMinutes, issue.Estimate))
+	}
+	if issue.TimerStartedAt != "" {
+		fmt.Printf("Timer: %s\n", formatTimer(issue, time.Now()))
+	}
+	if len(hierarchy.Children) > 0 {
+		if summary, err := pebbles.SubtreeTime(root, issue.ID); err == nil && (summary.LoggedMinutes > 0 || summary.EstimateMinutes > 0 || summary.EstimatePoints > 0) {
+			fmt.Printf("Subtree time: %s\n", formatSubtreeTime(summary))
+		}
+	}
+	if len(issue.Labels) > 0 {
+		fmt.Printf("Labels: %s\n", renderLabels(issue.Labels))
+	}
+	if len(issue.Fields) > 0 {
+		fmt.Println("Fields:")
+		for _, name := range orderedFieldNames(issue.Fields) {
+			fmt.Printf("  %s: %s\n", name, issue.Fields[name])
+		}
+	}
 	if len(hierarchy.Parents) > 0 {
 		label := "Parent"
 		if len(hierarchy.Parents) > 1 {
@@ -1081,10 +2000,17 @@ func printIssue(root string, issue pebbles.Issue, hierarchy pebbles.IssueHierarc
 		fmt.Printf("%s: %s\n", label, strings.Join(issueIDsFromIssues(hierarchy.Parents), ", "))
 	}
 	fmt.Printf(
-		"Created: %s · Updated: %s\n\n",
+		"Created: %s · Updated: %s\n",
 		formatDate(issue.CreatedAt),
 		formatDate(issue.UpdatedAt),
 	)
+	if activeWorkflow.IsDone(issue.Status) && issue.ClosedAt != "" {
+		fmt.Println(formatClosedLine(issue))
+	}
+	if issue.DeletedAt != "" {
+		fmt.Println(formatDeletedLine(issue))
+	}
+	fmt.Println()
 	// Description section.
 	fmt.Println("DESCRIPTION")
 	if strings.TrimSpace(issue.Description) == "" {
@@ -1094,6 +2020,7 @@ func printIssue(root string, issue pebbles.Issue, hierarchy pebbles.IssueHierarc
 	}
 	// Parent-child relationships provide context for epics/subtasks.
 	printIssueHierarchy(hierarchy)
+	printIssueRelationSection("DUPLICATES", duplicates.Duplicates, false)
 	// Dependency list with status per dependency.
 	fmt.Println("\nDEPENDENCIES")
 	if len(deps) == 0 {
@@ -1158,13 +2085,135 @@ func printIssueComments(comments []pebbles.IssueComment) {
 		if index > 0 {
 			fmt.Println("")
 		}
-		fmt.Printf("  %s\n", formatCommentTimestamp(comment.Timestamp))
+		fmt.Printf("  %s\n", formatCommentHeader(comment))
 		for _, line := range formatCommentBodyLines(comment.Body) {
 			fmt.Printf("    %s\n", line)
 		}
 	}
 }
 
+// formatClosedLine summarizes when and why an issue was closed.
+func formatClosedLine(issue pebbles.Issue) string {
+	line := fmt.Sprintf("Closed: %s", formatDate(issue.ClosedAt))
+	if issue.Resolution != "" {
+		line += fmt.Sprintf(" · Resolution: %s", issue.Resolution)
+	}
+	if issue.CloseReason != "" {
+		line += fmt.Sprintf(" · Reason: %s", issue.CloseReason)
+	}
+	return line
+}
+
+// formatDeletedLine summarizes when and why an issue was deleted.
+func formatDeletedLine(issue pebbles.Issue) string {
+	line := colorize(fmt.Sprintf("Deleted: %s", formatDate(issue.DeletedAt)), ansiRed)
+	if issue.DeleteReason != "" {
+		line += fmt.Sprintf(" · Reason: %s", issue.DeleteReason)
+	}
+	return line
+}
+
+// formatDueLine renders the due date with a colored relative description.
+// Done issues show only the date.
+func formatDueLine(issue pebbles.Issue, now time.Time) string {
+	if activeWorkflow.IsDone(issue.Status) {
+		return issue.DueAt
+	}
+	days, _ := pebbles.DueInDays(issue.DueAt, now)
+	return fmt.Sprintf("%s (%s)", issue.DueAt, colorize(pebbles.FormatDueRelative(issue.DueAt, now), dueColor(days)))
+}
+
+// formatTimeLine compares logged time with an estimate. Time estimates get a
+// percentage; point estimates are shown alongside.
+func formatTimeLine(loggedMinutes int, estimate string) string {
+	logged := pebbles.FormatMinutes(loggedMinutes) + " logged"
+	if estimate == "" {
+		return logged
+	}
+	if points, ok := pebbles.EstimatePoints(estimate); ok {
+		return fmt.Sprintf("%s · estimate %s", logged, formatPoints(points))
+	}
+	estimateMinutes, _ := pebbles.EstimateMinutes(estimate)
+	line := fmt.Sprintf("%s of %s estimated", logged, estimate)
+	if estimateMinutes > 0 {
+		percent := loggedMinutes * 100 / estimateMinutes
+		text := fmt.Sprintf("(%d%%)", percent)
+		if percent > 100 {
+			text = colorize(text, ansiRed)
+		}
+		line += " " + text
+	}
+	return line
+}
+
+// formatSubtreeTime renders the time rolled up over a parent-child subtree.
+func formatSubtreeTime(summary pebbles.TimeSummary) string {
+	var estimates []string
+	if summary.EstimateMinutes > 0 {
+		estimates = append(estimates, pebbles.FormatMinutes(summary.EstimateMinutes))
+	}
+	if summary.EstimatePoints > 0 {
+		estimates = append(estimates, formatPoints(summary.EstimatePoints))
+	}
+	line := pebbles.FormatMinutes(summary.LoggedMinutes) + " logged"
+	if len(estimates) > 0 {
+		line += " of " + strings.Join(estimates, " + ") + " estimated"
+	}
+	return fmt.Sprintf("%s across %d issues", line, summary.Issues)
+}
+
+// formatPoints renders story points such as "3 pts" or "0.5 pts".
+func formatPoints(points float64) string {
+	text := fmt.Sprintf("%g", points)
+	if points == 1 {
+		return text + " pt"
+	}
+	return text + " pts"
+}
+
+// formatTimer describes a running timer and how long it has run.
+func formatTimer(issue pebbles.Issue, now time.Time) string {
+	started := issue.TimerStartedAt
+	if parsed, err := time.Parse(time.RFC3339Nano, started); err == nil {
+		started = parsed.Local().Format("2006-01-02 15:04")
+	}
+	line := "running since " + started
+	if minutes, err := pebbles.TimerMinutes(issue.TimerStartedAt, now); err == nil {
+		line += fmt.Sprintf(" (%s so far)", pebbles.FormatMinutes(minutes))
+	}
+	if issue.TimerStartedBy != "" {
+		line += " by " + issue.TimerStartedBy
+	}
+	return line
+}
+
+// formatClaim describes who holds a claim and when it runs out.
+func formatClaim(issue pebbles.Issue, now time.Time) string {
+	expires := issue.ClaimExpiresAt
+	if parsed, err := time.Parse(time.RFC3339Nano, expires); err == nil {
+		expires = parsed.Local().Format("2006-01-02 15:04")
+	}
+	if !issue.ClaimActive(now) {
+		return fmt.Sprintf("%s (expired %s)", issue.ClaimedBy, expires)
+	}
+	return fmt.Sprintf("%s until %s", issue.ClaimedBy, expires)
+}
+
+// formatCommentHeader renders the comment timestamp and actor, when recorded.
+func formatCommentHeader(comment pebbles.IssueComment) string {
+	header := formatCommentTimestamp(comment.Timestamp)
+	if actor := strings.TrimSpace(comment.Actor); actor != "" {
+		header += " " + colorize(actor, ansiCyan)
+	}
+	if comment.ID != "" {
+		header += " " + colorize(comment.ID, ansiDim)
+	}
+	if comment.EditedAt != "" {
+		header += " " + colorize("(edited)", ansiDim)
+	}
+	return header
+}
+
 // printUsage prints a brief usage message.
 func printUsage() {
 	fmt.Print(rootHelp)
@@ -1172,13 +2221,84 @@ func printUsage() {
 
 // listFilters holds optional filters for pb list output.
 type listFilters struct {
-	statuses   map[string]bool
-	types      map[string]bool
-	priorities map[int]bool
+	statuses    map[string]bool
+	types       map[string]bool
+	priorities  map[int]bool
+	labels      []string
+	noLabel     bool
+	assignees   map[string]bool
+	resolutions map[string]bool
+	hideDone    bool
+	// fields maps custom field names to required values; "" means unset.
+	fields map[string]string
+	// overdue keeps open issues past their due date; dueBefore keeps issues
+	// due on or before a YYYY-MM-DD date.
+	overdue   bool
+	dueBefore string
+	// deferred keeps open issues whose deferral has not yet passed.
+	deferred bool
+	// ids, when set, keeps only the issues a --where expression selected.
+	ids map[string]bool
+}
+
+// parseWhereFilter runs a --where expression against the cache and returns
+// the matching issue IDs, or nil when no expression was given. The bool
+// reports whether the expression looks at status, resolution, or close time,
+// which turns off list's default hiding of done issues.
+func parseWhereFilter(root, input string) (map[string]bool, bool, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, false, nil
+	}
+	expr, err := pebbles.ParseWhere(input, activeFields, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	ids, err := pebbles.FilterIssueIDs(root, expr)
+	if err != nil {
+		return nil, false, err
+	}
+	mentionsStatus := false
+	for _, name := range pebbles.WhereFields(expr) {
+		if name == "status" || name == "resolution" || name == "closed" {
+			mentionsStatus = true
+		}
+	}
+	return ids, mentionsStatus, nil
+}
+
+// listOrder holds the --sort and --limit options shared by list and ready.
+type listOrder struct {
+	// keys is nil when no --sort was given, keeping the command's own order.
+	keys  []pebbles.SortKey
+	limit int
+}
+
+// parseListOrder validates the --sort and --limit flags.
+func parseListOrder(sortSpec string, limit int) (listOrder, error) {
+	if limit < 0 {
+		return listOrder{}, fmt.Errorf("limit must not be negative")
+	}
+	order := listOrder{limit: limit}
+	if strings.TrimSpace(sortSpec) != "" {
+		keys, err := pebbles.ParseSortKeys(sortSpec)
+		if err != nil {
+			return listOrder{}, err
+		}
+		order.keys = keys
+	}
+	return order, nil
+}
+
+// count returns how many of n results to print under --limit.
+func (order listOrder) count(n int) int {
+	if order.limit > 0 && order.limit < n {
+		return order.limit
+	}
+	return n
 }
 
 // parseListFilters builds the filter set for pb list.
-func parseListFilters(statusInput, typeInput, priorityInput string) (listFilters, error) {
+func parseListFilters(statusInput, typeInput, priorityInput, labelInput string, noLabel bool) (listFilters, error) {
 	statuses, err := parseListStatusFilter(statusInput)
 	if err != nil {
 		return listFilters{}, err
@@ -1187,35 +2307,107 @@ func parseListFilters(statusInput, typeInput, priorityInput string) (listFilters
 	if err != nil {
 		return listFilters{}, err
 	}
+	labels, err := parseListLabelFilter(labelInput)
+	if err != nil {
+		return listFilters{}, err
+	}
+	if noLabel && len(labels) > 0 {
+		return listFilters{}, fmt.Errorf("--label and --no-label cannot be combined")
+	}
+	types, err := parseListTypeFilter(typeInput)
+	if err != nil {
+		return listFilters{}, err
+	}
 	return listFilters{
 		statuses:   statuses,
-		types:      parseListTypeFilter(typeInput),
+		types:      types,
 		priorities: priorities,
+		labels:     labels,
+		noLabel:    noLabel,
 	}, nil
 }
 
-// parseListStatusFilter validates and normalizes status filters.
-func parseListStatusFilter(input string) (map[string]bool, error) {
+// parseAssigneeFilter builds an assignee filter; --mine adds the local actor.
+func parseAssigneeFilter(root, input string, mine bool) (map[string]bool, error) {
+	values := splitCSV(input)
+	if mine {
+		actor := pebbles.ResolveActor(root)
+		if actor == "" {
+			return nil, fmt.Errorf("--mine needs an identity: set %s, config actor, or git user.email", pebbles.ActorEnvVar)
+		}
+		values = append(values, actor)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	assignees := make(map[string]bool, len(values))
+	for _, value := range values {
+		assignees[strings.ToLower(value)] = true
+	}
+	return assignees, nil
+}
+
+// parseListResolutionFilter validates resolution filters.
+func parseListResolutionFilter(input string) (map[string]bool, error) {
+	values := splitCSV(input)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	resolutions := make(map[string]bool, len(values))
+	for _, value := range values {
+		resolution, err := pebbles.ParseResolution(value)
+		if err != nil {
+			return nil, err
+		}
+		resolutions[resolution] = true
+	}
+	return resolutions, nil
+}
+
+// parseListLabelFilter normalizes label filters; every label must match.
+func parseListLabelFilter(input string) ([]string, error) {
 	values := splitCSV(input)
 	if len(values) == 0 {
 		return nil, nil
 	}
-	allowed := map[string]bool{
-		pebbles.StatusOpen:       true,
-		pebbles.StatusInProgress: true,
-		pebbles.StatusClosed:     true,
+	labels := make([]string, 0, len(values))
+	for _, value := range values {
+		label, err := pebbles.NormalizeLabel(value)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// parseListStatusFilter validates status filters against the workflow. A
+// category name (todo, active, done) that is not itself a status expands to
+// every status in that category.
+func parseListStatusFilter(input string) (map[string]bool, error) {
+	values := splitCSV(input)
+	if len(values) == 0 {
+		return nil, nil
 	}
 	statuses := make(map[string]bool, len(values))
 	// Normalize and validate each status value.
 	for _, value := range values {
-		normalized := normalizeStatusFilter(value)
-		if normalized == "" {
+		if strings.TrimSpace(value) == "" {
 			continue
 		}
-		if !allowed[normalized] {
-			return nil, fmt.Errorf("unknown status: %s", value)
+		status, err := activeWorkflow.ParseStatus(value)
+		if err != nil {
+			category := strings.ToLower(strings.TrimSpace(value))
+			inCategory := activeWorkflow.StatusesIn(category)
+			if len(inCategory) == 0 {
+				return nil, err
+			}
+			for _, name := range inCategory {
+				statuses[name] = true
+			}
+			continue
 		}
-		statuses[normalized] = true
+		statuses[status] = true
 	}
 	if len(statuses) == 0 {
 		return nil, nil
@@ -1223,25 +2415,48 @@ func parseListStatusFilter(input string) (map[string]bool, error) {
 	return statuses, nil
 }
 
-// parseListTypeFilter normalizes the type filter to lowercase.
-func parseListTypeFilter(input string) map[string]bool {
+// orderedFieldNames lists an issue's fields in config order, followed by any
+// fields no longer declared, sorted by name.
+func orderedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for _, name := range activeFields.Names() {
+		if _, ok := fields[name]; ok {
+			names = append(names, name)
+		}
+	}
+	var undeclared []string
+	for name := range fields {
+		if _, ok := activeFields.Lookup(name); !ok {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	return append(names, undeclared...)
+}
+
+// parseListTypeFilter normalizes the type filter to lowercase, rejecting
+// types the project does not declare.
+func parseListTypeFilter(input string) (map[string]bool, error) {
 	values := splitCSV(input)
 	if len(values) == 0 {
-		return nil
+		return nil, nil
 	}
 	types := make(map[string]bool, len(values))
 	// Normalize types to lowercase for case-insensitive matching.
 	for _, value := range values {
-		normalized := strings.ToLower(strings.TrimSpace(value))
-		if normalized == "" {
+		if strings.TrimSpace(value) == "" {
 			continue
 		}
-		types[normalized] = true
+		normalized, err := activeIssueTypes.ParseType(value)
+		if err != nil {
+			return nil, err
+		}
+		types[strings.ToLower(normalized)] = true
 	}
 	if len(types) == 0 {
-		return nil
+		return nil, nil
 	}
-	return types
+	return types, nil
 }
 
 // parseListPriorityFilter parses priority filters into numeric values.
@@ -1270,12 +2485,46 @@ func (filters listFilters) matches(issue pebbles.Issue) bool {
 	if filters.statuses != nil && !filters.statuses[issue.Status] {
 		return false
 	}
+	if filters.hideDone && activeWorkflow.IsDone(issue.Status) {
+		return false
+	}
+	if filters.ids != nil && !filters.ids[issue.ID] {
+		return false
+	}
+	for name, value := range filters.fields {
+		if issue.Fields[name] != value {
+			return false
+		}
+	}
+	if filters.overdue && (activeWorkflow.IsDone(issue.Status) || !pebbles.IsOverdue(issue, time.Now())) {
+		return false
+	}
+	if filters.dueBefore != "" && (issue.DueAt == "" || issue.DueAt > filters.dueBefore) {
+		return false
+	}
+	if filters.deferred && (activeWorkflow.IsDone(issue.Status) || !issue.DeferActive(time.Now())) {
+		return false
+	}
 	if filters.types != nil && !filters.types[strings.ToLower(issue.IssueType)] {
 		return false
 	}
 	if filters.priorities != nil && !filters.priorities[issue.Priority] {
 		return false
 	}
+	if filters.resolutions != nil && (!activeWorkflow.IsDone(issue.Status) || !filters.resolutions[issue.Resolution]) {
+		return false
+	}
+	if filters.assignees != nil && !filters.assignees[strings.ToLower(issue.Assignee)] {
+		return false
+	}
+	if filters.noLabel && len(issue.Labels) > 0 {
+		return false
+	}
+	for _, label := range filters.labels {
+		if !issue.HasLabel(label) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -1297,14 +2546,9 @@ func splitCSV(input string) []string {
 	return values
 }
 
-// normalizeStatusFilter allows hyphenated status names in filters.
-func normalizeStatusFilter(input string) string {
-	trimmed := strings.ToLower(strings.TrimSpace(input))
-	return strings.ReplaceAll(trimmed, "-", "_")
-}
-
 // exitError prints an error to stderr and exits.
 func exitError(err error) {
+	snapshotCleanup()
 	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	os.Exit(1)
 }
@@ -1359,12 +2603,12 @@ func staleIssueWidthsForRows(rows []staleIssueRow) staleIssueWidths {
 
 // updateIssueColumnWidths expands column widths to fit the issue fields.
 func updateIssueColumnWidths(widths *issueColumnWidths, issue pebbles.Issue) {
-	statusIcon := pebbles.StatusIcon(issue.Status)
+	statusIcon := activeWorkflow.StatusIcon(issue.Status)
 	priority := priorityDisplay(issue)
 	widths.status = maxWidth(widths.status, displayWidth(statusIcon))
 	widths.id = maxWidth(widths.id, displayWidth(issue.ID))
 	widths.priority = maxWidth(widths.priority, displayWidth(priority))
-	widths.issueType = maxWidth(widths.issueType, displayWidth(issue.IssueType))
+	widths.issueType = maxWidth(widths.issueType, displayWidth(issueTypeDisplay(issue.IssueType)))
 }
 
 // maxWidth returns the larger of two widths.
@@ -1422,13 +2666,14 @@ func formatIssueLine(issue pebbles.Issue, depth int, widths issueColumnWidths) s
 	priorityLabel := fmt.Sprintf("● %s", renderPriorityLabel(issue.Priority))
 	issueType := renderIssueType(issue.IssueType)
 	return fmt.Sprintf(
-		"%s%s %s [%s] [%s] - %s",
+		"%s%s %s [%s] [%s] - %s%s",
 		indent,
 		padDisplay(statusIcon, widths.status),
 		padDisplay(issue.ID, widths.id),
 		padDisplay(priorityLabel, widths.priority),
 		padDisplay(issueType, widths.issueType),
 		issue.Title,
+		issueSuffix(issue),
 	)
 }
 
@@ -1439,7 +2684,7 @@ func formatStaleIssueLine(row staleIssueRow, widths staleIssueWidths) string {
 	priorityLabel := fmt.Sprintf("● %s", renderPriorityLabel(row.Item.Issue.Priority))
 	issueType := renderIssueType(row.Item.Issue.IssueType)
 	return fmt.Sprintf(
-		"%s%s %s [%s] [%s] [%s] - %s",
+		"%s%s %s [%s] [%s] [%s] - %s%s",
 		indent,
 		padDisplay(statusIcon, widths.issue.status),
 		padDisplay(row.Item.Issue.ID, widths.issue.id),
@@ -1447,6 +2692,7 @@ func formatStaleIssueLine(row staleIssueRow, widths staleIssueWidths) string {
 		padDisplay(issueType, widths.issue.issueType),
 		padDisplay(row.Activity, widths.activity),
 		row.Item.Issue.Title,
+		issueSuffix(row.Item.Issue),
 	)
 }
 
@@ -1473,6 +2719,26 @@ func issueLastActivity(issue pebbles.Issue, activityByID map[string]time.Time) (
 	return time.Time{}, fmt.Errorf("missing activity timestamp for %s", issue.ID)
 }
 
+// issueSuffix renders the assignee, labels, and date tags that trail a list
+// line.
+func issueSuffix(issue pebbles.Issue) string {
+	suffix := ""
+	if issue.Assignee != "" {
+		suffix += " " + renderAssignee(issue.Assignee)
+	}
+	if len(issue.Labels) > 0 {
+		suffix += " " + renderLabels(issue.Labels)
+	}
+	if issue.DueAt != "" && !activeWorkflow.IsDone(issue.Status) {
+		days, _ := pebbles.DueInDays(issue.DueAt, time.Now())
+		suffix += " " + colorize("due:"+issue.DueAt, dueColor(days))
+	}
+	if issue.DeferActive(time.Now()) && !activeWorkflow.IsDone(issue.Status) {
+		suffix += " " + colorize("deferred:"+issue.DeferredUntil, ansiDim)
+	}
+	return suffix
+}
+
 // formatBlockedIssueLine appends blocker IDs to a list output line.
 func formatBlockedIssueLine(issue pebbles.Issue, blockers []pebbles.Issue, widths issueColumnWidths) string {
 	line := formatIssueLine(issue, 0, widths)
@@ -1560,7 +2826,7 @@ func reorderFlags(args []string, flagsWithValues map[string]bool) []string {
 func printDepTree(node pebbles.DepNode, depth int, targetID string) {
 	indent := strings.Repeat("  ", depth)
 	priorityLabel := pebbles.PriorityLabel(node.Issue.Priority)
-	issueType := node.Issue.IssueType
+	issueType := issueTypeDisplay(node.Issue.IssueType)
 	issueID := node.Issue.ID
 	if issueID == targetID {
 		issueID = maybeBold(issueID)
@@ -1568,7 +2834,7 @@ func printDepTree(node pebbles.DepNode, depth int, targetID string) {
 	line := fmt.Sprintf(
 		"%s%s %s (%s) [%s %s] - %s",
 		indent,
-		pebbles.StatusIcon(node.Issue.Status),
+		activeWorkflow.StatusIcon(node.Issue.Status),
 		issueID,
 		node.Issue.Status,
 		priorityLabel,
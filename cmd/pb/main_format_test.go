@@ -55,3 +55,56 @@ func TestRenderMarkdownHighlights(t *testing.T) {
 		t.Fatalf("expected code text in output: %q", output)
 	}
 }
+
+// TestFormatDoctorProblem verifies doctor diagnostics use file:line prefixes.
+func TestFormatDoctorProblem(t *testing.T) {
+	previous := colorEnabled
+	colorEnabled = false
+	t.Cleanup(func() {
+		colorEnabled = previous
+	})
+	problem := pebbles.LogProblem{Line: 7, Code: pebbles.ProblemMissingIssue, IssueID: "pb-1", Message: "close event targets missing issue pb-1"}
+	got := formatDoctorProblem(problem)
+	want := "events.jsonl:7: missing_issue: [pb-1] close event targets missing issue pb-1"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+// TestFormatSkippedWarning verifies skipped events are summarized by type.
+func TestFormatSkippedWarning(t *testing.T) {
+	skipped := []pebbles.SkippedEvent{{Type: "teleport"}, {Type: "archive"}, {Type: "teleport"}}
+	got := formatSkippedWarning(skipped)
+	want := "skipped 3 events this version of pb cannot apply (archive x1, teleport x2); see pb log or upgrade pb"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+// TestFormatTimeLines verifies estimate-versus-actual and subtree rollup text.
+func TestFormatTimeLines(t *testing.T) {
+	previous := colorEnabled
+	colorEnabled = false
+	defer func() {
+		colorEnabled = previous
+	}()
+
+	cases := []struct {
+		logged   int
+		estimate string
+		want     string
+	}{
+		{90, "4h", "1h30m logged of 4h estimated (37%)"},
+		{45, "3", "45m logged · estimate 3 pts"},
+		{30, "", "30m logged"},
+	}
+	for _, tc := range cases {
+		if got := formatTimeLine(tc.logged, tc.estimate); got != tc.want {
+			t.Fatalf("formatTimeLine(%d, %q) = %q, want %q", tc.logged, tc.estimate, got, tc.want)
+		}
+	}
+	summary := pebbles.TimeSummary{Issues: 3, LoggedMinutes: 150, EstimateMinutes: 240, EstimatePoints: 1}
+	if got := formatSubtreeTime(summary); got != "2h30m logged of 4h + 1 pt estimated across 3 issues" {
+		t.Fatalf("unexpected subtree time %q", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"pebbles/internal/pebbles"
 )
@@ -160,3 +161,393 @@ func TestListJSONRespectsDefaultAndAll(t *testing.T) {
 		t.Fatalf("expected --json --all output to include closed; ids=%v output=%q", ids, outAll)
 	}
 }
+
+func TestListLabelFilters(t *testing.T) {
+	root, openID, inProgressID, _ := setupListProject(t)
+	events := []pebbles.Event{
+		pebbles.NewLabelAddEvent(openID, "frontend", "2024-01-02T00:00:00Z"),
+		pebbles.NewLabelAddEvent(openID, "bug", "2024-01-02T00:01:00Z"),
+		pebbles.NewLabelAddEvent(inProgressID, "frontend", "2024-01-02T00:02:00Z"),
+	}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		t.Fatalf("append labels: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	previous := colorEnabled
+	colorEnabled = false
+	defer func() {
+		colorEnabled = previous
+	}()
+
+	out := captureStdout(t, func() {
+		runList(root, []string{"--label", "frontend,BUG"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) {
+		t.Fatalf("expected --label to require every label; output=%q", out)
+	}
+	if !strings.Contains(out, "#bug #frontend") {
+		t.Fatalf("expected labels in list output; output=%q", out)
+	}
+
+	out = captureStdout(t, func() {
+		runList(root, []string{"--no-label", "--all"})
+	})
+	if strings.Contains(out, openID) || strings.Contains(out, inProgressID) || !strings.Contains(out, "pb-closed") {
+		t.Fatalf("expected --no-label to show only unlabelled issues; output=%q", out)
+	}
+
+	out = captureStdout(t, func() {
+		runList(root, []string{"--json", "--label", "bug"})
+	})
+	var got []struct {
+		ID     string   `json:"id"`
+		Labels []string `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal json: %v; output=%q", err, out)
+	}
+	if len(got) != 1 || strings.Join(got[0].Labels, ",") != "bug,frontend" {
+		t.Fatalf("expected labels in json output; got=%+v", got)
+	}
+}
+
+func TestReadyMineFiltersByActor(t *testing.T) {
+	root, openID, inProgressID, _ := setupListProject(t)
+	t.Setenv(pebbles.ActorEnvVar, "Me@example.com")
+	events := []pebbles.Event{
+		pebbles.NewAssignEvent(openID, "me@example.com", "2024-01-02T00:00:00Z"),
+		pebbles.NewAssignEvent(inProgressID, "other@example.com", "2024-01-02T00:01:00Z"),
+	}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		t.Fatalf("append assign events: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runReady(root, []string{"--mine"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) {
+		t.Fatalf("expected --mine to show only the actor's issues; output=%q", out)
+	}
+
+	out = captureStdout(t, func() {
+		runList(root, []string{"--assignee", "other@example.com"})
+	})
+	if strings.Contains(out, openID) || !strings.Contains(out, inProgressID) {
+		t.Fatalf("expected --assignee to filter list output; output=%q", out)
+	}
+	if !strings.Contains(out, "@other@example.com") {
+		t.Fatalf("expected assignee tag in list output; output=%q", out)
+	}
+}
+
+func TestListResolutionFilter(t *testing.T) {
+	root, openID, _, closedID := setupListProject(t)
+	event := pebbles.NewResolvedCloseEvent(openID, pebbles.ResolutionWontFix, "Not needed", "2024-01-02T00:00:00Z")
+	if err := pebbles.AppendEvent(root, event); err != nil {
+		t.Fatalf("append close: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runList(root, []string{"--resolution", "wont-fix"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, closedID) {
+		t.Fatalf("expected --resolution to show only wontfix issues; output=%q", out)
+	}
+}
+
+func TestListHidesConfiguredDoneStatuses(t *testing.T) {
+	root, openID, inProgressID, closedID := setupListProject(t)
+	workflow, err := pebbles.NewWorkflow([]pebbles.StatusConfig{
+		{Name: "open", Category: "todo"},
+		{Name: "in_progress", Category: "active"},
+		{Name: "shipped", Category: "done"},
+		{Name: "closed", Category: "done"},
+	})
+	if err != nil {
+		t.Fatalf("new workflow: %v", err)
+	}
+	previous := activeWorkflow
+	activeWorkflow = workflow
+	defer func() { activeWorkflow = previous }()
+	if err := pebbles.AppendEvent(root, pebbles.NewStatusEvent(inProgressID, "shipped", "2024-01-02T00:00:00Z")); err != nil {
+		t.Fatalf("append status: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runList(root, nil)
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) || strings.Contains(out, closedID) {
+		t.Fatalf("expected default list to hide done statuses; output=%q", out)
+	}
+	out = captureStdout(t, func() {
+		runList(root, []string{"--status", "done"})
+	})
+	if strings.Contains(out, openID) || !strings.Contains(out, inProgressID) || !strings.Contains(out, closedID) {
+		t.Fatalf("expected --status done to show every done status; output=%q", out)
+	}
+}
+
+func TestShowPrintsClosedLineForConfiguredDoneStatuses(t *testing.T) {
+	root, _, inProgressID, _ := setupListProject(t)
+	workflow, err := pebbles.NewWorkflow([]pebbles.StatusConfig{
+		{Name: "open", Category: "todo"},
+		{Name: "in_progress", Category: "active"},
+		{Name: "wontfix", Category: "done"},
+		{Name: "closed", Category: "done"},
+	})
+	if err != nil {
+		t.Fatalf("new workflow: %v", err)
+	}
+	previous := activeWorkflow
+	activeWorkflow = workflow
+	defer func() { activeWorkflow = previous }()
+	event := pebbles.NewStatusEventWithCategory(inProgressID, "wontfix", pebbles.StatusCategoryDone, "2024-01-02T00:00:00Z")
+	if err := pebbles.AppendEvent(root, event); err != nil {
+		t.Fatalf("append status: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runShow(root, []string{inProgressID})
+	})
+	if !strings.Contains(out, "Closed: ") {
+		t.Fatalf("expected a Closed line for a done status; output=%q", out)
+	}
+}
+
+func TestListTypeFilterRejectsUndeclaredTypes(t *testing.T) {
+	issueTypes, err := pebbles.NewIssueTypes([]pebbles.IssueTypeConfig{
+		{Name: "task"},
+		{Name: "bug", Icon: "B", Color: "green"},
+	}, pebbles.CustomFields{})
+	if err != nil {
+		t.Fatalf("new issue types: %v", err)
+	}
+	previous := activeIssueTypes
+	activeIssueTypes = issueTypes
+	defer func() { activeIssueTypes = previous }()
+
+	if _, err := parseListTypeFilter("task,bgu"); err == nil || !strings.Contains(err.Error(), "did you mean bug?") {
+		t.Fatalf("expected suggestion for bgu, got %v", err)
+	}
+	types, err := parseListTypeFilter("BUG")
+	if err != nil || !types["bug"] {
+		t.Fatalf("expected bug filter, got %v (%v)", types, err)
+	}
+	if typeColor("bug") != ansiBrightGreen || issueTypeDisplay("bug") != "B bug" {
+		t.Fatalf("expected configured color and icon for bug")
+	}
+}
+
+func TestListDueFilters(t *testing.T) {
+	root, openID, inProgressID, closedID := setupListProject(t)
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	events := []pebbles.Event{
+		pebbles.NewDueEvent(openID, yesterday, "2024-01-02T00:00:00Z"),
+		pebbles.NewDueEvent(inProgressID, nextWeek, "2024-01-02T00:00:01Z"),
+		pebbles.NewDueEvent(closedID, yesterday, "2024-01-02T00:00:02Z"),
+	}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		t.Fatalf("append due events: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runList(root, []string{"--overdue", "--all"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) || strings.Contains(out, closedID) {
+		t.Fatalf("expected --overdue to show only the open overdue issue; output=%q", out)
+	}
+	if !strings.Contains(out, "due:"+yesterday) {
+		t.Fatalf("expected due tag in list output; output=%q", out)
+	}
+	out = captureStdout(t, func() {
+		runList(root, []string{"--due-before", "+7d"})
+	})
+	if !strings.Contains(out, openID) || !strings.Contains(out, inProgressID) {
+		t.Fatalf("expected --due-before +7d to include both open issues; output=%q", out)
+	}
+	out = captureStdout(t, func() {
+		runList(root, []string{"--due-before", "today"})
+	})
+	if strings.Contains(out, inProgressID) {
+		t.Fatalf("expected --due-before today to exclude next week's issue; output=%q", out)
+	}
+}
+
+// TestListDeferredFilter verifies --deferred shows only issues deferred past
+// today and tags them with their wake-up date.
+func TestListDeferredFilter(t *testing.T) {
+	root, openID, inProgressID, _ := setupListProject(t)
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	events := []pebbles.Event{
+		pebbles.NewDeferEvent(openID, nextWeek, "2024-01-02T00:00:00Z"),
+		pebbles.NewDeferEvent(inProgressID, yesterday, "2024-01-02T00:00:01Z"),
+	}
+	if err := pebbles.AppendEvents(root, events); err != nil {
+		t.Fatalf("append defer events: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runList(root, []string{"--deferred"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) {
+		t.Fatalf("expected --deferred to show only the issue deferred to next week; output=%q", out)
+	}
+	if !strings.Contains(out, "deferred:"+nextWeek) {
+		t.Fatalf("expected deferred tag in list output; output=%q", out)
+	}
+}
+
+// TestSearchIncludesClosedAndAppliesFilters verifies pb search shows done
+// issues by default, highlights matches, and honors --status.
+func TestListWhereFilter(t *testing.T) {
+	root, openID, inProgressID, closedID := setupListProject(t)
+
+	out := captureStdout(t, func() {
+		runList(root, []string{"--where", "title ~ progress or status = open"})
+	})
+	if !strings.Contains(out, openID) || !strings.Contains(out, inProgressID) || strings.Contains(out, closedID) {
+		t.Fatalf("expected --where to match the open and in-progress issues; output=%q", out)
+	}
+
+	// Mentioning status in --where turns off the default hiding of done issues.
+	out = captureStdout(t, func() {
+		runList(root, []string{"--where", "status = closed"})
+	})
+	if !strings.Contains(out, closedID) || strings.Contains(out, openID) {
+		t.Fatalf("expected --where status = closed to show the closed issue; output=%q", out)
+	}
+}
+
+func TestListAndReadySortAndLimit(t *testing.T) {
+	root, openID, inProgressID, closedID := setupListProject(t)
+	if err := pebbles.AppendEvent(root, pebbles.NewUpdateEvent(openID, "2024-01-02T00:00:00Z", map[string]string{"priority": "0"})); err != nil {
+		t.Fatalf("append priority update: %v", err)
+	}
+	if err := pebbles.RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runList(root, []string{"--all", "--sort", "-created", "--limit", "2"})
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], closedID) || !strings.Contains(lines[1], inProgressID) {
+		t.Fatalf("expected the two newest issues, newest first; output=%q", out)
+	}
+
+	// JSON output follows the same order, so the first element is the top pick.
+	out = captureStdout(t, func() {
+		runReady(root, []string{"--sort", "title", "--json"})
+	})
+	var ready []issueJSON
+	if err := json.Unmarshal([]byte(out), &ready); err != nil {
+		t.Fatalf("decode ready json: %v", err)
+	}
+	if len(ready) != 2 || ready[0].ID != inProgressID {
+		t.Fatalf("expected ready sorted by title; got %+v", ready)
+	}
+	out = captureStdout(t, func() {
+		runReady(root, []string{"--limit", "1"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) {
+		t.Fatalf("expected the P0 issue first in the default ready order; output=%q", out)
+	}
+}
+
+func TestRunViewAppliesSavedFilters(t *testing.T) {
+	root, openID, inProgressID, closedID := setupListProject(t)
+	cfg, err := pebbles.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Views = []pebbles.ViewConfig{
+		{Name: "active", Where: "status != open", Sort: "-created", Columns: []string{"id", "status"}},
+	}
+	if err := pebbles.WriteConfig(root, cfg); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		runView(root, []string{"active"})
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") {
+		t.Fatalf("expected a header and two rows; output=%q", out)
+	}
+	if !strings.HasPrefix(lines[1], closedID) || !strings.Contains(lines[1], "closed") || !strings.HasPrefix(lines[2], inProgressID) {
+		t.Fatalf("expected newest first with status column; output=%q", out)
+	}
+	if strings.Contains(out, openID) {
+		t.Fatalf("expected the where filter to drop open issues; output=%q", out)
+	}
+
+	out = captureStdout(t, func() {
+		runView(root, []string{"list"})
+	})
+	if !strings.Contains(out, "active") || !strings.Contains(out, "status != open") {
+		t.Fatalf("expected view list to show the view and its filter; output=%q", out)
+	}
+}
+
+func TestListAsOfShowsEarlierState(t *testing.T) {
+	root, openID, inProgressID, closedID := setupListProject(t)
+
+	// Before the close event, the closed issue was still open.
+	out := captureStdout(t, func() {
+		runList(root, []string{"--as-of", "2024-01-01T00:35:00Z"})
+	})
+	if !strings.Contains(out, openID) || !strings.Contains(out, inProgressID) || !strings.Contains(out, closedID) {
+		t.Fatalf("expected all three issues open as of 00:35; output=%q", out)
+	}
+	out = captureStdout(t, func() {
+		runReady(root, []string{"--as-of", "2024-01-01T00:05:00Z"})
+	})
+	if !strings.Contains(out, openID) || strings.Contains(out, inProgressID) {
+		t.Fatalf("expected only the first issue as of 00:05; output=%q", out)
+	}
+}
+
+func TestSearchIncludesClosedAndAppliesFilters(t *testing.T) {
+	previous := colorEnabled
+	colorEnabled = false
+	defer func() {
+		colorEnabled = previous
+	}()
+	root, _, _, closedID := setupListProject(t)
+
+	out := captureStdout(t, func() {
+		runSearch(root, []string{"closed"})
+	})
+	if !strings.Contains(out, closedID) || !strings.Contains(out, "**Closed**") {
+		t.Fatalf("expected the closed issue with a highlighted snippet; output=%q", out)
+	}
+	out = captureStdout(t, func() {
+		runSearch(root, []string{"closed", "--status", "open"})
+	})
+	if strings.Contains(out, closedID) {
+		t.Fatalf("expected --status open to drop the closed issue; output=%q", out)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"pebbles/internal/pebbles"
+)
+
+// runResolve handles pb resolve.
+func runResolve(root string, args []string) {
+	fs := flag.NewFlagSet("resolve", flag.ExitOnError)
+	setFlagUsage(fs, resolveHelp)
+	dryRun := fs.Bool("dry-run", false, "Report changes without rewriting the log")
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		exitError(fmt.Errorf("resolve takes no arguments"))
+	}
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	resolution, err := pebbles.ResolveConflicts(root, *dryRun)
+	if err != nil {
+		exitError(err)
+	}
+	if len(resolution.Blocks) == 0 {
+		fmt.Println("No conflict markers found")
+		return
+	}
+	for _, block := range resolution.Blocks {
+		fmt.Println(formatConflictBlock(block))
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: %d conflict blocks found; events.jsonl not changed\n", len(resolution.Blocks))
+		return
+	}
+	fmt.Printf("Resolved %d conflict blocks in events.jsonl\n", len(resolution.Blocks))
+}
+
+// formatConflictBlock describes what pb resolve kept and dropped for a block.
+func formatConflictBlock(block pebbles.ConflictBlock) string {
+	line := fmt.Sprintf(
+		"lines %d-%d: kept %d events (%d ours, %d theirs), dropped %d duplicates",
+		block.StartLine,
+		block.EndLine,
+		block.Kept,
+		block.Ours,
+		block.Theirs,
+		block.Duplicates,
+	)
+	if block.Base > 0 {
+		line += fmt.Sprintf(", dropped %d base lines", block.Base)
+	}
+	return line
+}
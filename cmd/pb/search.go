@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"pebbles/internal/pebbles"
+)
+
+// searchResultJSON is one pb search match: the list issue fields plus the
+// snippet, with matched terms wrapped in ** markers.
+type searchResultJSON struct {
+	issueJSON
+	Snippet string `json:"snippet"`
+}
+
+// runSearch handles pb search.
+func runSearch(root string, args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	setFlagUsage(fs, searchHelp)
+	status := fs.String("status", "", "Filter by status (comma-separated)")
+	issueType := fs.String("type", "", "Filter by issue type (comma-separated)")
+	priority := fs.String("priority", "", "Filter by priority (P0-P4, comma-separated)")
+	jsonOut := fs.Bool("json", false, "Output JSON")
+	where := fs.String("where", "", "Filter with an expression (see pb list --help)")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{"--status": true, "--type": true, "--priority": true, "--where": true}))
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	query := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		exitError(fmt.Errorf("search requires a query"))
+	}
+	filters, err := parseListFilters(*status, *issueType, *priority, "", false)
+	if err != nil {
+		exitError(err)
+	}
+	if filters.ids, _, err = parseWhereFilter(root, *where); err != nil {
+		exitError(err)
+	}
+	results, err := pebbles.SearchIssues(root, query)
+	if err != nil {
+		exitError(err)
+	}
+	// Keep rank order while applying the list filters.
+	matched := make([]pebbles.SearchResult, 0, len(results))
+	for _, result := range results {
+		if filters.matches(result.Issue) {
+			matched = append(matched, result)
+		}
+	}
+	if *jsonOut {
+		entries := make([]searchResultJSON, 0, len(matched))
+		for _, result := range matched {
+			entry, err := issueJSONWithDeps(root, result.Issue)
+			if err != nil {
+				exitError(err)
+			}
+			entries = append(entries, searchResultJSON{issueJSON: entry, Snippet: renderSnippet(result.Snippet, false)})
+		}
+		if err := printJSON(entries); err != nil {
+			exitError(err)
+		}
+		return
+	}
+	issues := make([]pebbles.Issue, 0, len(matched))
+	for _, result := range matched {
+		issues = append(issues, result.Issue)
+	}
+	widths := issueColumnWidthsForIssues(issues)
+	for _, result := range matched {
+		fmt.Println(formatIssueLine(result.Issue, 0, widths))
+		if snippet := renderSnippet(result.Snippet, colorEnabled); snippet != "" {
+			fmt.Printf("    %s\n", snippet)
+		}
+	}
+}
+
+// renderSnippet flattens a search snippet onto one line and highlights the
+// matched terms, in color when enabled and with ** markers otherwise.
+func renderSnippet(snippet string, color bool) string {
+	start, end := "**", "**"
+	if color {
+		start, end = ansiBold+ansiYellow, ansiReset
+	}
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	snippet = strings.ReplaceAll(snippet, pebbles.SnippetMatchStart, start)
+	return strings.ReplaceAll(snippet, pebbles.SnippetMatchEnd, end)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"pebbles/internal/pebbles"
+)
+
+// runView handles pb view.
+func runView(root string, args []string) {
+	fs := flag.NewFlagSet("view", flag.ExitOnError)
+	setFlagUsage(fs, viewHelp)
+	jsonOut := fs.Bool("json", false, "Output JSON regardless of the view's format")
+	_ = fs.Parse(reorderFlags(args, map[string]bool{}))
+	if err := ensureProject(root); err != nil {
+		exitError(err)
+	}
+	if fs.NArg() != 1 {
+		exitError(fmt.Errorf("usage: pb view <name> | pb view list"))
+	}
+	views, err := pebbles.LoadViews(root, activeFields)
+	if err != nil {
+		exitError(err)
+	}
+	if fs.Arg(0) == "list" {
+		printViews(views)
+		return
+	}
+	view, ok := pebbles.FindView(views, fs.Arg(0))
+	if !ok {
+		exitError(fmt.Errorf("unknown view %q (see pb view list)", fs.Arg(0)))
+	}
+	if *jsonOut {
+		view.Format = pebbles.ViewFormatJSON
+	}
+	runSavedView(root, view)
+}
+
+// printViews prints one line per view: its name, then its description or,
+// without one, its where expression.
+func printViews(views []pebbles.View) {
+	if len(views) == 0 {
+		fmt.Println("No views defined (add \"views\" to .pebbles/config.json).")
+		return
+	}
+	width := 0
+	for _, view := range views {
+		width = maxWidth(width, displayWidth(view.Name))
+	}
+	for _, view := range views {
+		summary := view.Description
+		if summary == "" {
+			summary = view.Where
+		}
+		parts := []string{padDisplay(view.Name, width)}
+		if summary != "" {
+			parts = append(parts, summary)
+		}
+		if view.Personal {
+			parts = append(parts, colorize("(personal)", ansiDim))
+		}
+		fmt.Println(strings.TrimRight(strings.Join(parts, "  "), " "))
+	}
+}
+
+// runSavedView lists the issues a view selects in the view's format.
+func runSavedView(root string, view pebbles.View) {
+	assignees, err := parseAssigneeFilter(root, "", view.Mine)
+	if err != nil {
+		exitError(err)
+	}
+	filters := listFilters{assignees: assignees}
+	whereStatus := false
+	filters.ids, whereStatus, err = parseWhereFilter(root, view.Where)
+	if err != nil {
+		exitError(fmt.Errorf("view %s: %w", view.Name, err))
+	}
+	// Like pb list, hide done issues unless the view asks about them.
+	filters.hideDone = !view.All && !whereStatus
+	order, err := parseListOrder(view.Sort, view.Limit)
+	if err != nil {
+		exitError(fmt.Errorf("view %s: %w", view.Name, err))
+	}
+	var items []pebbles.IssueHierarchyItem
+	if view.Ready {
+		ready, err := pebbles.ListReadyIssues(root)
+		if err != nil {
+			exitError(err)
+		}
+		for _, issue := range ready {
+			items = append(items, pebbles.IssueHierarchyItem{Issue: issue})
+		}
+	} else {
+		items, err = pebbles.ListIssueHierarchy(root)
+		if err != nil {
+			exitError(err)
+		}
+	}
+	matched := make([]pebbles.IssueHierarchyItem, 0, len(items))
+	for _, item := range items {
+		if filters.matches(item.Issue) {
+			matched = append(matched, item)
+		}
+	}
+	if order.keys != nil {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return pebbles.IssueLess(matched[i].Issue, matched[j].Issue, order.keys)
+		})
+		for i := range matched {
+			matched[i].Depth = 0
+		}
+	}
+	matched = matched[:order.count(len(matched))]
+	switch view.Format {
+	case pebbles.ViewFormatJSON:
+		entries := make([]issueJSON, 0, len(matched))
+		for _, item := range matched {
+			entry, err := issueJSONWithDeps(root, item.Issue)
+			if err != nil {
+				exitError(err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := printJSON(entries); err != nil {
+			exitError(err)
+		}
+	case pebbles.ViewFormatTable:
+		printViewTable(matched, view.Columns)
+	default:
+		widths := issueColumnWidthsForHierarchy(matched)
+		for _, item := range matched {
+			fmt.Println(formatIssueLine(item.Issue, item.Depth, widths))
+		}
+	}
+}
+
+// defaultViewColumns are shown by table views that list no columns.
+var defaultViewColumns = []string{"id", "status", "priority", "type", "title"}
+
+// printViewTable prints issues as aligned columns under an upper-case header.
+func printViewTable(items []pebbles.IssueHierarchyItem, columns []string) {
+	if len(columns) == 0 {
+		columns = defaultViewColumns
+	}
+	rows := make([][]string, 0, len(items)+1)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	rows = append(rows, header)
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = viewColumnValue(item.Issue, column)
+		}
+		rows = append(rows, row)
+	}
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, value := range row {
+			widths[i] = maxWidth(widths[i], displayWidth(value))
+		}
+	}
+	for index, row := range rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = padDisplay(value, widths[i])
+		}
+		text := strings.TrimRight(strings.Join(cells, "  "), " ")
+		if index == 0 {
+			text = colorize(text, ansiBold)
+		}
+		fmt.Println(text)
+	}
+}
+
+// viewColumnValue returns an issue's value for a table column. Columns that
+// are not built-in are custom fields.
+func viewColumnValue(issue pebbles.Issue, column string) string {
+	switch column {
+	case "id":
+		return issue.ID
+	case "title":
+		return issue.Title
+	case "status":
+		return issue.Status
+	case "type":
+		return issue.IssueType
+	case "priority":
+		return pebbles.PriorityLabel(issue.Priority)
+	case "assignee":
+		return issue.Assignee
+	case "labels":
+		return strings.Join(issue.Labels, ",")
+	case "due":
+		return issue.DueAt
+	case "deferred":
+		return issue.DeferredUntil
+	case "created":
+		return formatDate(issue.CreatedAt)
+	case "updated":
+		return formatDate(issue.UpdatedAt)
+	case "closed":
+		return formatDate(issue.ClosedAt)
+	case "estimate":
+		return issue.Estimate
+	case "logged":
+		if issue.LoggedMinutes == 0 {
+			return ""
+		}
+		return pebbles.FormatMinutes(issue.LoggedMinutes)
+	}
+	return issue.Fields[column]
+}
@@ -0,0 +1,34 @@
+package pebbles
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ActorEnvVar overrides the actor recorded on new events.
+const ActorEnvVar = "PEBBLES_ACTOR"
+
+// ResolveActor returns the identity recorded on events appended from root.
+// PEBBLES_ACTOR wins, then the config.json "actor" setting, then the git
+// user.email for the repository. It returns "" when none are set.
+func ResolveActor(root string) string {
+	if actor := strings.TrimSpace(os.Getenv(ActorEnvVar)); actor != "" {
+		return actor
+	}
+	if cfg, err := LoadConfig(root); err == nil {
+		if actor := strings.TrimSpace(cfg.Actor); actor != "" {
+			return actor
+		}
+	}
+	return gitUserEmail(root)
+}
+
+// gitUserEmail reads git's user.email for the repository, if configured.
+func gitUserEmail(root string) string {
+	output, err := exec.Command("git", "-C", root, "config", "user.email").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
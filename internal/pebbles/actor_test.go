@@ -0,0 +1,55 @@
+package pebbles
+
+import "testing"
+
+func TestResolveActorPrefersEnvThenConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProjectWithPrefix(root, "pb"); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	if err := WriteConfig(root, Config{Prefix: "pb", Actor: "config@example.com"}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv(ActorEnvVar, "")
+	if actor := ResolveActor(root); actor != "config@example.com" {
+		t.Fatalf("expected config actor, got %q", actor)
+	}
+	t.Setenv(ActorEnvVar, "env@example.com")
+	if actor := ResolveActor(root); actor != "env@example.com" {
+		t.Fatalf("expected env actor, got %q", actor)
+	}
+}
+
+func TestAppendEventsStampsActor(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	t.Setenv(ActorEnvVar, "agent-7")
+	events := []Event{
+		NewCreateEvent("pb-1", "First", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewCommentEvent("pb-1", "hello", "2024-01-01T00:00:01Z"),
+	}
+	// An explicit actor, e.g. from an import, is kept as-is.
+	events[1].Actor = "alice@example.com"
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	loaded, err := LoadEvents(root)
+	if err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	if loaded[0].Actor != "agent-7" || loaded[1].Actor != "alice@example.com" {
+		t.Fatalf("unexpected actors: %q, %q", loaded[0].Actor, loaded[1].Actor)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	comments, err := ListIssueComments(root, "pb-1")
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Actor != "alice@example.com" {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+}
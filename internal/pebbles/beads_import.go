@@ -24,6 +24,7 @@ type beadsIssue struct {
 	Status       string            `json:"status"`
 	Priority     *int              `json:"priority"`
 	IssueType    string            `json:"issue_type"`
+	Assignee     string            `json:"assignee"`
 	CreatedAt    string            `json:"created_at"`
 	UpdatedAt    string            `json:"updated_at"`
 	ClosedAt     string            `json:"closed_at"`
@@ -33,6 +34,7 @@ type beadsIssue struct {
 	DeleteReason string            `json:"delete_reason"`
 	Dependencies []beadsDependency `json:"dependencies"`
 	Comments     []beadsComment    `json:"comments"`
+	Labels       []string          `json:"labels"`
 }
 
 type beadsDependency struct {
@@ -60,6 +62,9 @@ type BeadsImportOptions struct {
 	Prefix            string
 	IncludeTombstones bool
 	Now               func() time.Time
+	// IssueTypes are the target project's declared types; imported types
+	// are matched against them when set.
+	IssueTypes IssueTypes
 }
 
 // BeadsImportResult summarizes a Beads import plan or execution.
@@ -101,7 +106,7 @@ func PlanBeadsImport(options BeadsImportOptions) (BeadsImportPlan, error) {
 	}
 	warnings = append(warnings, prefixWarnings...)
 	// Build the import plan and aggregate all warnings.
-	plan, err := buildBeadsImportPlan(issues, options.IncludeTombstones, options.Now(), &warnings)
+	plan, err := buildBeadsImportPlan(issues, options.IncludeTombstones, options.IssueTypes, options.Now(), &warnings)
 	if err != nil {
 		return BeadsImportPlan{}, err
 	}
@@ -114,10 +119,12 @@ func PlanBeadsImport(options BeadsImportOptions) (BeadsImportPlan, error) {
 
 // ApplyBeadsImportPlan appends the planned events to the Pebbles log.
 func ApplyBeadsImportPlan(root string, plan BeadsImportPlan) (BeadsImportResult, error) {
-	for _, event := range plan.Events {
-		if err := AppendEvent(root, event); err != nil {
-			return BeadsImportResult{}, err
-		}
+	// Clocks follow the legacy replay order so imported history replays as it
+	// did before events carried clocks.
+	events := append([]Event(nil), plan.Events...)
+	sortEvents(events)
+	if err := AppendEvents(root, events); err != nil {
+		return BeadsImportResult{}, err
 	}
 	if err := RebuildCache(root); err != nil {
 		return BeadsImportResult{}, err
@@ -199,7 +206,7 @@ func resolveBeadsPrefix(issues []beadsIssue, override string) (string, []string,
 	return "", warnings, fmt.Errorf("unable to detect prefix")
 }
 
-func buildBeadsImportPlan(issues []beadsIssue, includeTombstones bool, now time.Time, warnings *[]string) (BeadsImportPlan, error) {
+func buildBeadsImportPlan(issues []beadsIssue, includeTombstones bool, issueTypes IssueTypes, now time.Time, warnings *[]string) (BeadsImportPlan, error) {
 	result := BeadsImportResult{IssuesTotal: len(issues)}
 	importedIDs := make(map[string]bool)
 	var imported []beadsIssue
@@ -241,11 +248,17 @@ func buildBeadsImportPlan(issues []beadsIssue, includeTombstones bool, now time.
 	var depAndCommentEvents []importEvent
 	var statusEvents []importEvent
 	for _, issue := range imported {
-		created := buildBeadsCreateEvent(issue, now, warnings)
+		created := buildBeadsCreateEvent(issue, issueTypes, now, warnings)
 		createEvents = append(createEvents, created)
 		for _, dep := range buildBeadsDependencyEvents(issue, importedIDs, now, warnings) {
 			depAndCommentEvents = append(depAndCommentEvents, dep)
 		}
+		for _, label := range buildBeadsLabelEvents(issue, now, warnings) {
+			depAndCommentEvents = append(depAndCommentEvents, label)
+		}
+		if assign, ok := buildBeadsAssignEvent(issue, now, warnings); ok {
+			depAndCommentEvents = append(depAndCommentEvents, assign)
+		}
 		for _, comment := range buildBeadsCommentEvents(issue, now, warnings) {
 			depAndCommentEvents = append(depAndCommentEvents, comment)
 		}
@@ -272,7 +285,7 @@ func buildBeadsImportPlan(issues []beadsIssue, includeTombstones bool, now time.
 	return plan, nil
 }
 
-func buildBeadsCreateEvent(issue beadsIssue, now time.Time, warnings *[]string) importEvent {
+func buildBeadsCreateEvent(issue beadsIssue, issueTypes IssueTypes, now time.Time, warnings *[]string) importEvent {
 	createdTime, createdStamp := resolveTimestamp(
 		[]string{issue.CreatedAt, issue.UpdatedAt},
 		now,
@@ -280,7 +293,7 @@ func buildBeadsCreateEvent(issue beadsIssue, now time.Time, warnings *[]string)
 		warnings,
 	)
 	priority := normalizeBeadsPriority(issue.Priority, issue.ID, warnings)
-	issueType := normalizeBeadsIssueType(issue.IssueType)
+	issueType := normalizeBeadsIssueType(issue.IssueType, issue.ID, issueTypes, warnings)
 	event := NewCreateEvent(issue.ID, issue.Title, issue.Description, issueType, createdStamp, priority)
 	return importEvent{Event: event, SortTime: createdTime, Order: 0}
 }
@@ -324,16 +337,55 @@ func buildBeadsDependencyEvents(issue beadsIssue, importedIDs map[string]bool, n
 	return events
 }
 
+func buildBeadsLabelEvents(issue beadsIssue, now time.Time, warnings *[]string) []importEvent {
+	var events []importEvent
+	seen := make(map[string]bool)
+	for _, raw := range issue.Labels {
+		label, err := NormalizeLabel(raw)
+		if err != nil {
+			*warnings = append(*warnings, fmt.Sprintf("issue %s label skipped: %v", issue.ID, err))
+			continue
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		// Beads does not timestamp labels, so attach them when the issue was created.
+		labelTime, labelStamp := resolveTimestamp(
+			[]string{issue.CreatedAt, issue.UpdatedAt},
+			now,
+			fmt.Sprintf("label on %s", issue.ID),
+			warnings,
+		)
+		event := NewLabelAddEvent(issue.ID, label, labelStamp)
+		events = append(events, importEvent{Event: event, SortTime: labelTime, Order: 1})
+	}
+	return events
+}
+
+func buildBeadsAssignEvent(issue beadsIssue, now time.Time, warnings *[]string) (importEvent, bool) {
+	assignee := strings.TrimSpace(issue.Assignee)
+	if assignee == "" {
+		return importEvent{}, false
+	}
+	assignTime, assignStamp := resolveTimestamp(
+		[]string{issue.CreatedAt, issue.UpdatedAt},
+		now,
+		fmt.Sprintf("assignee on %s", issue.ID),
+		warnings,
+	)
+	event := NewAssignEvent(issue.ID, assignee, assignStamp)
+	return importEvent{Event: event, SortTime: assignTime, Order: 1}, true
+}
+
 func buildBeadsCommentEvents(issue beadsIssue, now time.Time, warnings *[]string) []importEvent {
 	var events []importEvent
 	for _, comment := range issue.Comments {
-		// Preserve the author in the comment body since Pebbles lacks author metadata.
 		body := strings.TrimSpace(comment.Text)
 		if body == "" {
 			*warnings = append(*warnings, fmt.Sprintf("issue %s has empty comment", issue.ID))
 			continue
 		}
-		body = formatBeadsCommentBody(comment.Author, body)
 		commentTime, commentStamp := resolveTimestamp(
 			[]string{comment.CreatedAt, issue.UpdatedAt, issue.CreatedAt},
 			now,
@@ -341,6 +393,8 @@ func buildBeadsCommentEvents(issue beadsIssue, now time.Time, warnings *[]string
 			warnings,
 		)
 		event := NewCommentEvent(issue.ID, body, commentStamp)
+		// Keep the Beads author as the event actor.
+		event.Actor = strings.TrimSpace(comment.Author)
 		events = append(events, importEvent{Event: event, SortTime: commentTime, Order: 2})
 	}
 	// Capture close/delete reasons as a final comment entry.
@@ -378,8 +432,14 @@ func buildBeadsStatusEvents(issue beadsIssue, now time.Time, warnings *[]string)
 			fmt.Sprintf("close issue %s", issue.ID),
 			warnings,
 		)
-		event := NewCloseEvent(issue.ID, closeStamp)
+		event := NewResolvedCloseEvent(issue.ID, "", strings.TrimSpace(issue.CloseReason), closeStamp)
 		events = append(events, importEvent{Event: event, SortTime: closeTime, Order: 4})
+		if issue.Status == beadsStatusTombstone {
+			// Tombstones are also deleted, keeping the Beads deleter as the actor.
+			deleted := NewDeleteEvent(issue.ID, strings.TrimSpace(issue.DeleteReason), closeStamp)
+			deleted.Actor = strings.TrimSpace(issue.DeletedBy)
+			events = append(events, importEvent{Event: deleted, SortTime: closeTime, Order: 5})
+		}
 	}
 	return events
 }
@@ -422,28 +482,36 @@ func normalizeBeadsPriority(priority *int, issueID string, warnings *[]string) i
 	return value
 }
 
-func normalizeBeadsIssueType(issueType string) string {
+func normalizeBeadsIssueType(issueType, issueID string, issueTypes IssueTypes, warnings *[]string) string {
 	trimmed := strings.TrimSpace(issueType)
 	if trimmed == "" {
-		return "task"
+		trimmed = "task"
 	}
-	return trimmed
-}
-
-func formatBeadsCommentBody(author, text string) string {
-	trimmed := strings.TrimSpace(author)
-	if trimmed == "" {
-		return text
+	if !issueTypes.Restricted() {
+		return trimmed
 	}
-	return fmt.Sprintf("Author: %s\n%s", trimmed, text)
+	// Match declared types case-insensitively; keep undeclared types so no
+	// data is lost, but flag them for cleanup.
+	declared, err := issueTypes.ParseType(trimmed)
+	if err != nil {
+		*warnings = append(*warnings, fmt.Sprintf("issue %s: %v", issueID, err))
+		return trimmed
+	}
+	return declared
 }
 
 func buildBeadsReasonComment(issue beadsIssue) string {
 	var lines []string
-	// Capture any close or delete metadata in a comment body.
-	if strings.TrimSpace(issue.CloseReason) != "" {
+	// Close reasons ride on the close event; only keep them here when the
+	// issue is not being closed, so the text is not lost.
+	closing := issue.Status == StatusClosed || issue.Status == beadsStatusTombstone
+	if !closing && strings.TrimSpace(issue.CloseReason) != "" {
 		lines = append(lines, fmt.Sprintf("Close reason: %s", strings.TrimSpace(issue.CloseReason)))
 	}
+	// Tombstone delete details ride on the delete event instead.
+	if issue.Status == beadsStatusTombstone {
+		return strings.Join(lines, "\n")
+	}
 	if strings.TrimSpace(issue.DeleteReason) != "" {
 		lines = append(lines, fmt.Sprintf("Delete reason: %s", strings.TrimSpace(issue.DeleteReason)))
 	}
@@ -80,7 +80,7 @@ func TestPlanBeadsImportIncludesTombstones(t *testing.T) {
 		},
 	}
 	writeBeadsIssues(t, sourceRoot, issues)
-	// Include tombstones to ensure a close event is emitted.
+	// Include tombstones to ensure close and delete events are emitted.
 	now := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
 	plan, err := PlanBeadsImport(BeadsImportOptions{
 		SourceRoot:        sourceRoot,
@@ -101,6 +101,16 @@ func TestPlanBeadsImportIncludesTombstones(t *testing.T) {
 	if closeEvent.Timestamp != "2024-01-02T00:00:00Z" {
 		t.Fatalf("expected close timestamp to match deleted_at")
 	}
+	deleteEvent, ok := findEvent(plan.Events, EventTypeDelete, "zz-2b")
+	if !ok {
+		t.Fatalf("expected delete event for tombstone")
+	}
+	if deleteEvent.Timestamp != closeEvent.Timestamp {
+		t.Fatalf("expected delete timestamp %s, got %s", closeEvent.Timestamp, deleteEvent.Timestamp)
+	}
+	if _, ok := findEvent(plan.Events, EventTypeComment, "zz-2b"); ok {
+		t.Fatalf("expected no reason comment for tombstone without close reason")
+	}
 }
 
 func TestPlanBeadsImportParentChildDependency(t *testing.T) {
@@ -215,6 +225,96 @@ func TestPlanBeadsImportFallsBackToNowForMissingTimestamps(t *testing.T) {
 	}
 }
 
+func TestPlanBeadsImportKeepsCommentAuthorAsActor(t *testing.T) {
+	sourceRoot := t.TempDir()
+	issues := []beadsIssue{
+		{
+			ID:        "zz-1a",
+			Title:     "Commented issue",
+			Status:    "open",
+			Priority:  intPtr(2),
+			CreatedAt: "2024-01-01T00:00:00Z",
+			Comments: []beadsComment{
+				{Author: "alice@example.com", Text: "Looks good", CreatedAt: "2024-01-02T00:00:00Z"},
+			},
+		},
+	}
+	writeBeadsIssues(t, sourceRoot, issues)
+	plan, err := PlanBeadsImport(BeadsImportOptions{SourceRoot: sourceRoot})
+	if err != nil {
+		t.Fatalf("plan beads import: %v", err)
+	}
+	comment, ok := findEvent(plan.Events, EventTypeComment, "zz-1a")
+	if !ok {
+		t.Fatalf("expected comment event")
+	}
+	if comment.Actor != "alice@example.com" || comment.Payload["body"] != "Looks good" {
+		t.Fatalf("unexpected comment event: %+v", comment)
+	}
+}
+
+func TestPlanBeadsImportMapsLabels(t *testing.T) {
+	sourceRoot := t.TempDir()
+	issues := []beadsIssue{
+		{
+			ID:        "zz-1a",
+			Title:     "Labelled issue",
+			Status:    "open",
+			Priority:  intPtr(2),
+			CreatedAt: "2024-01-01T00:00:00Z",
+			Labels:    []string{"Frontend", "frontend", "needs review"},
+		},
+	}
+	writeBeadsIssues(t, sourceRoot, issues)
+	plan, err := PlanBeadsImport(BeadsImportOptions{SourceRoot: sourceRoot})
+	if err != nil {
+		t.Fatalf("plan beads import: %v", err)
+	}
+	// Duplicates collapse after normalization and invalid labels are warned about.
+	var labels []string
+	for _, event := range plan.Events {
+		if event.Type == EventTypeLabelAdd {
+			labels = append(labels, event.Payload["label"])
+		}
+	}
+	if len(labels) != 1 || labels[0] != "frontend" {
+		t.Fatalf("expected a single frontend label event, got %v", labels)
+	}
+	if len(plan.Result.Warnings) == 0 {
+		t.Fatalf("expected a warning for the invalid label")
+	}
+}
+
+func TestPlanBeadsImportMapsCloseReason(t *testing.T) {
+	sourceRoot := t.TempDir()
+	issues := []beadsIssue{
+		{
+			ID:          "zz-1a",
+			Title:       "Closed issue",
+			Status:      "closed",
+			Priority:    intPtr(2),
+			CreatedAt:   "2024-01-01T00:00:00Z",
+			ClosedAt:    "2024-01-02T00:00:00Z",
+			CloseReason: "Shipped in v2",
+		},
+	}
+	writeBeadsIssues(t, sourceRoot, issues)
+	plan, err := PlanBeadsImport(BeadsImportOptions{SourceRoot: sourceRoot})
+	if err != nil {
+		t.Fatalf("plan beads import: %v", err)
+	}
+	closeEvent, ok := findEvent(plan.Events, EventTypeClose, "zz-1a")
+	if !ok {
+		t.Fatalf("expected close event")
+	}
+	if closeEvent.Payload["reason"] != "Shipped in v2" {
+		t.Fatalf("expected close reason on the close event, got %+v", closeEvent.Payload)
+	}
+	if _, ok := findEvent(plan.Events, EventTypeComment, "zz-1a"); ok {
+		t.Fatalf("expected no synthetic close reason comment")
+	}
+}
+
 func writeBeadsIssues(t *testing.T, root string, issues []beadsIssue) {
 	t.Helper()
 	beadsDir := filepath.Join(root, ".beads")
@@ -1,15 +1,43 @@
 package pebbles
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
-	"sort"
+	"strconv"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+// cacheSchemaVersion identifies the cache layout; bump it when tables change.
+const cacheSchemaVersion = 18
+
+const (
+	metaSchemaVersion = "schema_version"
+	metaLogOffset     = "log_offset"
+	metaLogHash       = "log_hash"
+	metaMaxClock      = "max_clock"
+)
+
+// sqlExecutor is satisfied by both *sql.DB and *sql.Tx so replay can run in a transaction.
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// replayCheckpoint records how much of the event log the cache has consumed.
+type replayCheckpoint struct {
+	Offset   int64
+	Hash     string
+	MaxClock int64
+}
+
 // EnsureCache rebuilds the cache if the events log is newer than the DB.
 func EnsureCache(root string) error {
 	needs, err := needsRebuild(EventsPath(root), DBPath(root))
@@ -29,32 +57,234 @@ func EnsureCache(root string) error {
 	return nil
 }
 
-// RebuildCache recreates the SQLite cache from the event log.
+// RebuildCache brings the SQLite cache up to date with the event log.
+// Only events appended since the last replay are applied when the consumed
+// prefix of the log is unchanged; otherwise the cache is rebuilt from scratch.
 func RebuildCache(root string) error {
-	events, err := LoadEvents(root)
+	return WithLock(root, func() error {
+		return rebuildCache(root)
+	})
+}
+
+// rebuildCache replays the log into the cache; callers must hold the lock.
+func rebuildCache(root string) error {
+	data, err := os.ReadFile(EventsPath(root))
 	if err != nil {
-		return err
+		return fmt.Errorf("read events log: %w", err)
 	}
-	// Normalize event order before replay.
-	sortEvents(events)
 	db, err := openDB(DBPath(root))
 	if err != nil {
 		return err
 	}
 	defer func() { _ = db.Close() }()
+	// Prefer an incremental replay when the checkpoint still matches the log.
+	checkpoint, ok, err := loadReplayCheckpoint(db)
+	if err != nil {
+		return err
+	}
+	if ok && checkpoint.matches(data) {
+		return replayLogTail(db, data, checkpoint)
+	}
+	return replayFullLog(db, data)
+}
+
+// replayFullLog recreates the schema and replays every event in the log.
+func replayFullLog(db *sql.DB, data []byte) error {
+	events, err := parseEvents(data)
+	if err != nil {
+		return err
+	}
+	// Normalize event order before replay.
+	sortEvents(events)
+	checkpoint := newReplayCheckpoint(data)
+	checkpoint.MaxClock = maxEventClock(events)
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin cache rebuild: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
 	// Recreate schema and replay the event log.
-	if err := resetSchema(db); err != nil {
+	if err := resetSchema(tx); err != nil {
+		return err
+	}
+	if err := ensureSchema(tx); err != nil {
 		return err
 	}
-	if err := ensureSchema(db); err != nil {
+	if err := applyEvents(tx, events); err != nil {
 		return err
 	}
-	if err := applyEvents(db, events); err != nil {
+	if err := rebuildSearchIndex(tx); err != nil {
 		return err
 	}
+	if err := storeReplayCheckpoint(tx, checkpoint); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit cache rebuild: %w", err)
+	}
+	return nil
+}
+
+// replayLogTail applies events appended after the checkpoint offset.
+func replayLogTail(db *sql.DB, data []byte, checkpoint replayCheckpoint) error {
+	if checkpoint.Offset == int64(len(data)) {
+		return nil
+	}
+	// Number tail lines from the start of the file, not the checkpoint.
+	firstLine := bytes.Count(data[:checkpoint.Offset], []byte("\n")) + 1
+	events, err := parseEventsFrom(data[checkpoint.Offset:], firstLine)
+	if err != nil {
+		return err
+	}
+	// Events that are causally concurrent with already-applied ones (e.g. from
+	// a merged branch) must interleave with them, so replay everything.
+	if lowest, ok := minEventClock(events); ok && lowest <= checkpoint.MaxClock {
+		return replayFullLog(db, data)
+	}
+	sortEventsFrom(events, checkpoint.MaxClock)
+	next := newReplayCheckpoint(data)
+	next.MaxClock = checkpoint.MaxClock
+	if highest := maxEventClock(events); highest > next.MaxClock {
+		next.MaxClock = highest
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin cache replay: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	// Re-index only the issues the new events touched, under the IDs they
+	// had before and after the tail.
+	touched := make(map[string]bool)
+	if err := collectEventIssueIDs(tx, events, touched); err != nil {
+		return err
+	}
+	if err := applyEvents(tx, events); err != nil {
+		return err
+	}
+	if err := collectEventIssueIDs(tx, events, touched); err != nil {
+		return err
+	}
+	if err := reindexIssues(tx, touched); err != nil {
+		return err
+	}
+	if err := storeReplayCheckpoint(tx, next); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit cache replay: %w", err)
+	}
+	return nil
+}
+
+// newReplayCheckpoint builds a checkpoint covering the complete log contents.
+func newReplayCheckpoint(data []byte) replayCheckpoint {
+	return replayCheckpoint{Offset: int64(len(data)), Hash: hashLogPrefix(data)}
+}
+
+// matches reports whether the log still begins with the consumed prefix.
+func (checkpoint replayCheckpoint) matches(data []byte) bool {
+	if checkpoint.Offset < 0 || checkpoint.Offset > int64(len(data)) {
+		return false
+	}
+	// Only resume on a line boundary so a partial line is never skipped.
+	if checkpoint.Offset > 0 && data[checkpoint.Offset-1] != '\n' {
+		return false
+	}
+	return hashLogPrefix(data[:checkpoint.Offset]) == checkpoint.Hash
+}
+
+// hashLogPrefix returns the hex SHA-256 of a log prefix.
+func hashLogPrefix(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadReplayCheckpoint reads the stored checkpoint when the schema is current.
+func loadReplayCheckpoint(db sqlExecutor) (replayCheckpoint, bool, error) {
+	meta, err := loadCacheMeta(db)
+	if err != nil {
+		return replayCheckpoint{}, false, err
+	}
+	if meta[metaSchemaVersion] != strconv.Itoa(cacheSchemaVersion) {
+		return replayCheckpoint{}, false, nil
+	}
+	hash, ok := meta[metaLogHash]
+	if !ok {
+		return replayCheckpoint{}, false, nil
+	}
+	offset, err := strconv.ParseInt(meta[metaLogOffset], 10, 64)
+	if err != nil {
+		return replayCheckpoint{}, false, nil
+	}
+	maxClock, err := strconv.ParseInt(meta[metaMaxClock], 10, 64)
+	if err != nil {
+		return replayCheckpoint{}, false, nil
+	}
+	return replayCheckpoint{Offset: offset, Hash: hash, MaxClock: maxClock}, true, nil
+}
+
+// storeReplayCheckpoint persists the schema version and replay position.
+func storeReplayCheckpoint(db sqlExecutor, checkpoint replayCheckpoint) error {
+	values := map[string]string{
+		metaSchemaVersion: strconv.Itoa(cacheSchemaVersion),
+		metaLogOffset:     strconv.FormatInt(checkpoint.Offset, 10),
+		metaLogHash:       checkpoint.Hash,
+		metaMaxClock:      strconv.FormatInt(checkpoint.MaxClock, 10),
+	}
+	for key, value := range values {
+		if _, err := db.Exec(
+			"INSERT OR REPLACE INTO cache_meta (key, value) VALUES (?, ?)",
+			key,
+			value,
+		); err != nil {
+			return fmt.Errorf("store cache meta: %w", err)
+		}
+	}
 	return nil
 }
 
+// loadCacheMeta returns all cache metadata, or an empty map for older caches.
+func loadCacheMeta(db sqlExecutor) (map[string]string, error) {
+	meta := make(map[string]string)
+	exists, err := tableExists(db, "cache_meta")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return meta, nil
+	}
+	rows, err := db.Query("SELECT key, value FROM cache_meta")
+	if err != nil {
+		return nil, fmt.Errorf("load cache meta: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var key string
+		var value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("scan cache meta: %w", err)
+		}
+		meta[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cache meta rows: %w", err)
+	}
+	return meta, nil
+}
+
+// tableExists reports whether a table is present in the cache database.
+func tableExists(db sqlExecutor, name string) (bool, error) {
+	row := db.QueryRow("SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?", name)
+	var found int
+	if err := row.Scan(&found); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("check table %s: %w", name, err)
+	}
+	return true, nil
+}
+
 // needsRebuild compares timestamps to decide if the cache is stale.
 func needsRebuild(eventsPath, dbPath string) (bool, error) {
 	eventsInfo, err := os.Stat(eventsPath)
@@ -72,49 +302,18 @@ func needsRebuild(eventsPath, dbPath string) (bool, error) {
 	return eventsInfo.ModTime().After(dbInfo.ModTime()), nil
 }
 
-// needsSchemaUpdate checks whether the cache schema is missing expected columns.
+// needsSchemaUpdate checks whether the cache was built with an older schema.
 func needsSchemaUpdate(dbPath string) (bool, error) {
-	// Open the cache database and inspect the deps table schema.
 	db, err := openDB(dbPath)
 	if err != nil {
 		return false, err
 	}
 	defer func() { _ = db.Close() }()
-	hasDepType, err := depsTableHasColumn(db, "dep_type")
+	meta, err := loadCacheMeta(db)
 	if err != nil {
 		return false, err
 	}
-	// Trigger a rebuild if the new column is missing.
-	return !hasDepType, nil
-}
-
-// depsTableHasColumn reports whether the deps table contains a column name.
-func depsTableHasColumn(db *sql.DB, name string) (bool, error) {
-	// PRAGMA table_info returns one row per column.
-	rows, err := db.Query("PRAGMA table_info(deps)")
-	if err != nil {
-		return false, fmt.Errorf("deps schema: %w", err)
-	}
-	defer func() { _ = rows.Close() }()
-	// Scan column metadata looking for the requested name.
-	for rows.Next() {
-		var cid int
-		var colName string
-		var colType string
-		var notnull int
-		var dflt sql.NullString
-		var pk int
-		if err := rows.Scan(&cid, &colName, &colType, &notnull, &dflt, &pk); err != nil {
-			return false, fmt.Errorf("scan deps schema: %w", err)
-		}
-		if colName == name {
-			return true, nil
-		}
-	}
-	if err := rows.Err(); err != nil {
-		return false, fmt.Errorf("deps schema rows: %w", err)
-	}
-	return false, nil
+	return meta[metaSchemaVersion] != strconv.Itoa(cacheSchemaVersion), nil
 }
 
 // openDB opens a SQLite database at the given path.
@@ -126,7 +325,25 @@ func openDB(path string) (*sql.DB, error) {
 	return db, nil
 }
 
-// sortEvents orders events by timestamp with a stable fallback.
+// parseEvents decodes JSONL event data, skipping blank lines.
+func parseEvents(data []byte) ([]Event, error) {
+	return parseEventsFrom(data, 1)
+}
+
+// parseEventsFrom decodes JSONL event data that starts at line firstLine of
+// the log file.
+func parseEventsFrom(data []byte, firstLine int) ([]Event, error) {
+	var events []Event
+	for _, line := range splitLogLinesFrom(data, firstLine) {
+		event, err := decodeLogLine(line)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
 // eventTypePriority returns a sort order for event types.
 // Creates must come before deps/comments, which must come before status changes.
 func eventTypePriority(eventType string) int {
@@ -141,32 +358,35 @@ func eventTypePriority(eventType string) int {
 		return 3
 	}
 }
+
+// sortEvents orders events for replay; see replayOrder.
 func sortEvents(events []Event) {
-	// Preserve original ordering by embedding an index.
-	type indexed struct {
-		Event
-		Index int
-	}
-	indexedEvents := make([]indexed, 0, len(events))
-	for i, event := range events {
-		indexedEvents = append(indexedEvents, indexed{Event: event, Index: i})
-	}
-	// Sort by event type priority, then timestamp, then original index.
-	// This ensures creates come before deps, which come before status changes.
-	sort.SliceStable(indexedEvents, func(i, j int) bool {
-		priI := eventTypePriority(indexedEvents[i].Type)
-		priJ := eventTypePriority(indexedEvents[j].Type)
-		if priI != priJ {
-			return priI < priJ
-		}
-		timeI, errI := time.Parse(time.RFC3339Nano, indexedEvents[i].Timestamp)
-		timeJ, errJ := time.Parse(time.RFC3339Nano, indexedEvents[j].Timestamp)
-		if errI == nil && errJ == nil && !timeI.Equal(timeJ) {
-			return timeI.Before(timeJ)
-		}
-		return indexedEvents[i].Index < indexedEvents[j].Index
-	})
-	for i := range indexedEvents {
-		events[i] = indexedEvents[i].Event
+	sortEventsFrom(events, 0)
+}
+
+// sortEventsFrom orders events for replay after a prefix that reached baseClock.
+func sortEventsFrom(events []Event, baseClock int64) {
+	order := replayOrder(events, baseClock)
+	sorted := make([]Event, len(events))
+	for i, index := range order {
+		sorted[i] = events[index]
+	}
+	copy(events, sorted)
+}
+
+// legacyEventLess orders events that carry no clock, by type priority then
+// timestamp. This ensures creates come before deps, which come before status
+// changes. Events that compare equal keep their log order under a stable sort.
+func legacyEventLess(a, b Event) bool {
+	priA := eventTypePriority(a.Type)
+	priB := eventTypePriority(b.Type)
+	if priA != priB {
+		return priA < priB
+	}
+	timeA, errA := time.Parse(time.RFC3339Nano, a.Timestamp)
+	timeB, errB := time.Parse(time.RFC3339Nano, b.Timestamp)
+	if errA == nil && errB == nil && !timeA.Equal(timeB) {
+		return timeA.Before(timeB)
 	}
+	return false
 }
@@ -1,6 +1,12 @@
 package pebbles
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
 
 func TestSortEventsOrdersRenameBeforeDeps(t *testing.T) {
 	timestamp := "2026-01-19T00:00:00Z"
@@ -51,3 +57,257 @@ func TestRebuildCacheIgnoresDuplicateCreateEvents(t *testing.T) {
 		t.Fatalf("expected 1 issue, got %d", len(issues))
 	}
 }
+
+func TestRebuildCacheReplaysAppendedEvents(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	if err := AppendEvent(root, NewCreateEvent("pb-first", "First", "", "task", "2024-01-01T00:00:00Z", 2)); err != nil {
+		t.Fatalf("append first create: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	if err := AppendEvent(root, NewCreateEvent("pb-second", "Second", "", "task", "2024-01-01T00:00:01Z", 2)); err != nil {
+		t.Fatalf("append second create: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache after append: %v", err)
+	}
+	issues, err := ListIssues(root)
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	// The checkpoint should cover the whole log after the incremental replay.
+	info, err := os.Stat(EventsPath(root))
+	if err != nil {
+		t.Fatalf("stat events log: %v", err)
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	checkpoint, ok, err := loadReplayCheckpoint(db)
+	if err != nil {
+		t.Fatalf("load checkpoint: %v", err)
+	}
+	if !ok || checkpoint.Offset != info.Size() {
+		t.Fatalf("expected checkpoint at offset %d, got %+v (ok=%v)", info.Size(), checkpoint, ok)
+	}
+}
+
+func TestRebuildCacheRebuildsWhenPrefixChanges(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	if err := AppendEvent(root, NewCreateEvent("pb-keep", "Keep", "", "task", "2024-01-01T00:00:00Z", 2)); err != nil {
+		t.Fatalf("append keep create: %v", err)
+	}
+	if err := AppendEvent(root, NewCreateEvent("pb-drop", "Drop", "", "task", "2024-01-01T00:00:01Z", 2)); err != nil {
+		t.Fatalf("append drop create: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	// Rewrite the log so the consumed prefix no longer matches the checkpoint.
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		t.Fatalf("read events log: %v", err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if err := os.WriteFile(EventsPath(root), []byte(lines[0]), 0600); err != nil {
+		t.Fatalf("rewrite events log: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache after rewrite: %v", err)
+	}
+	issues, err := ListIssues(root)
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "pb-keep" {
+		t.Fatalf("expected only pb-keep after full rebuild, got %+v", issues)
+	}
+}
+
+func TestRebuildCacheSkipsUnknownEventTypes(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	// A newer client wrote an event type and payload shape this version does not know.
+	future := `{"version":99,"type":"teleport","timestamp":"2024-01-01T00:00:01Z","issue_id":"pb-1","payload":{"to":{"planet":"mars"},"hops":3}}`
+	lines := []string{
+		`{"type":"create","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"title":"First"}}`,
+		future,
+		`{"version":99,"type":"update","timestamp":"2024-01-01T00:00:02Z","issue_id":"pb-1","payload":{"color":"red"}}`,
+		`{"type":"close","timestamp":"2024-01-01T00:00:03Z","issue_id":"pb-1","payload":{}}`,
+	}
+	if err := os.WriteFile(EventsPath(root), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-1")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.Status != StatusClosed {
+		t.Fatalf("expected known events to apply, got status %s", issue.Status)
+	}
+	skipped, err := ListSkippedEvents(root)
+	if err != nil {
+		t.Fatalf("list skipped events: %v", err)
+	}
+	if len(skipped) != 2 || skipped[0].Type != "teleport" || skipped[1].Type != EventTypeUpdate {
+		t.Fatalf("unexpected skipped events: %+v", skipped)
+	}
+	// The log keeps the raw line and a stringified payload for pb log.
+	entries, err := LoadEventLog(root)
+	if err != nil {
+		t.Fatalf("load event log: %v", err)
+	}
+	if entries[1].Raw != future {
+		t.Fatalf("expected raw line to be kept, got %q", entries[1].Raw)
+	}
+	if entries[1].Event.Payload["to"] != `{"planet":"mars"}` || entries[1].Event.Payload["hops"] != "3" {
+		t.Fatalf("unexpected payload: %+v", entries[1].Event.Payload)
+	}
+}
+
+func TestSortEventsOrdersByClock(t *testing.T) {
+	events := []Event{
+		{Type: EventTypeDepRemove, IssueID: "pb-1", Timestamp: "2026-01-19T00:00:00Z", Clock: 3},
+		{Type: EventTypeStatus, IssueID: "pb-1", Timestamp: "2026-01-19T00:00:05Z", Clock: 2},
+		{Type: EventTypeCreate, IssueID: "pb-1", Timestamp: "2026-01-19T00:00:09Z", Clock: 1},
+	}
+	sortEvents(events)
+	// Clocks win over type priority and skewed timestamps.
+	expected := []string{EventTypeCreate, EventTypeStatus, EventTypeDepRemove}
+	for i, eventType := range expected {
+		if events[i].Type != eventType {
+			t.Fatalf("expected %s at index %d, got %s", eventType, i, events[i].Type)
+		}
+	}
+}
+
+func TestSortEventsBreaksClockTiesDeterministically(t *testing.T) {
+	first := Event{Type: EventTypeComment, IssueID: "pb-1", Timestamp: "2026-01-19T00:00:00Z", Clock: 4, Payload: map[string]string{"body": "a"}}
+	second := Event{Type: EventTypeComment, IssueID: "pb-1", Timestamp: "2026-01-19T00:00:00Z", Clock: 4, Payload: map[string]string{"body": "b"}}
+	// Both branches' file orders must produce the same replay order.
+	left := []Event{first, second}
+	right := []Event{second, first}
+	sortEvents(left)
+	sortEvents(right)
+	for i := range left {
+		if left[i].Payload["body"] != right[i].Payload["body"] {
+			t.Fatalf("expected identical order, got %v and %v", left, right)
+		}
+	}
+}
+
+func TestAppendEventsStampsClocks(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	batch := []Event{
+		NewCreateEvent("pb-1", "First", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewCommentEvent("pb-1", "hello", "2024-01-01T00:00:00Z"),
+	}
+	if err := AppendEvents(root, batch); err != nil {
+		t.Fatalf("append batch: %v", err)
+	}
+	if err := AppendEvent(root, NewCloseEvent("pb-1", "2024-01-01T00:00:00Z")); err != nil {
+		t.Fatalf("append close: %v", err)
+	}
+	events, err := LoadEvents(root)
+	if err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	for i, event := range events {
+		if event.Clock != int64(i+1) {
+			t.Fatalf("expected clock %d for %s, got %d", i+1, event.Type, event.Clock)
+		}
+	}
+}
+
+func TestRebuildCacheTailReportsFileLineNumbers(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	for i, id := range []string{"pb-1", "pb-2", "pb-3", "pb-4", "pb-5"} {
+		if err := AppendEvent(root, NewCreateEvent(id, "Issue", "", "task", fmt.Sprintf("2024-01-01T00:00:0%dZ", i), 2)); err != nil {
+			t.Fatalf("append create: %v", err)
+		}
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		t.Fatalf("read events log: %v", err)
+	}
+	for tail, want := range map[string]string{
+		"not json\n":               "parse event line 6",
+		"<<<<<<< HEAD\n":           "line 6",
+		"\n{\"type\":\"create\"\n": "parse event line 7",
+	} {
+		if err := os.WriteFile(EventsPath(root), append(append([]byte(nil), data...), tail...), 0600); err != nil {
+			t.Fatalf("write events log: %v", err)
+		}
+		err := RebuildCache(root)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("tail %q: expected error mentioning %q, got %v", tail, want, err)
+		}
+		var conflict *ConflictMarkerError
+		if errors.As(err, &conflict) && conflict.Line != 6 {
+			t.Fatalf("expected conflict marker on line 6, got %d", conflict.Line)
+		}
+	}
+}
+
+func TestRebuildCacheInterleavesConcurrentTail(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	lines := []string{
+		`{"clock":1,"type":"create","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"title":"First"}}`,
+		`{"clock":3,"type":"close","timestamp":"2024-01-01T00:00:03Z","issue_id":"pb-1","payload":{}}`,
+	}
+	if err := os.WriteFile(EventsPath(root), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	// A merged branch appends an event that happened before the close.
+	merged := `{"clock":2,"type":"status_update","timestamp":"2024-01-01T00:00:09Z","issue_id":"pb-1","payload":{"status":"in_progress"}}` + "\n"
+	file, err := os.OpenFile(EventsPath(root), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("open events log: %v", err)
+	}
+	if _, err := file.WriteString(merged); err != nil {
+		t.Fatalf("append merged event: %v", err)
+	}
+	_ = file.Close()
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache after merge: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-1")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.Status != StatusClosed {
+		t.Fatalf("expected causal replay to leave pb-1 closed, got %s", issue.Status)
+	}
+}
@@ -0,0 +1,114 @@
+package pebbles
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultClaimLease is how long a claim lasts when no lease is given.
+const DefaultClaimLease = 2 * time.Hour
+
+// ErrNothingToClaim reports that no ready, unclaimed issue is available.
+var ErrNothingToClaim = errors.New("no ready issues to claim")
+
+// ClaimActive reports whether the issue holds a claim that has not expired.
+func (issue Issue) ClaimActive(now time.Time) bool {
+	if issue.ClaimedBy == "" || issue.ClaimExpiresAt == "" {
+		return false
+	}
+	expires, err := time.Parse(time.RFC3339Nano, issue.ClaimExpiresAt)
+	if err != nil {
+		return false
+	}
+	return now.Before(expires)
+}
+
+// ClaimIssue claims an issue for actor until the lease runs out. When id is
+// empty the first ready issue is chosen. Selection and the append
+// happen under the project lock, so concurrent claimers never take the same
+// issue.
+func ClaimIssue(root, id, actor string, lease time.Duration) (Issue, error) {
+	if actor == "" {
+		return Issue{}, fmt.Errorf("claim requires an actor: set %s, config actor, or git user.email", ActorEnvVar)
+	}
+	if lease <= 0 {
+		return Issue{}, fmt.Errorf("claim lease must be positive")
+	}
+	var claimed Issue
+	err := WithLock(root, func() error {
+		now := time.Now().UTC()
+		issue, err := claimCandidate(root, id, actor, now)
+		if err != nil {
+			return err
+		}
+		event := NewClaimEvent(issue.ID, now.Add(lease).Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
+		event.Actor = actor
+		if err := AppendEvent(root, event); err != nil {
+			return err
+		}
+		if err := RebuildCache(root); err != nil {
+			return err
+		}
+		claimed, _, err = GetIssue(root, issue.ID)
+		return err
+	})
+	if err != nil {
+		return Issue{}, err
+	}
+	return claimed, nil
+}
+
+// claimCandidate picks the issue to claim; callers must hold the lock.
+func claimCandidate(root, id, actor string, now time.Time) (Issue, error) {
+	if id != "" {
+		issue, _, err := GetIssue(root, id)
+		if err != nil {
+			return Issue{}, err
+		}
+		workflow, err := LoadWorkflow(root)
+		if err != nil {
+			return Issue{}, err
+		}
+		if workflow.IsDone(issue.Status) {
+			return Issue{}, fmt.Errorf("issue %s is %s", issue.ID, issue.Status)
+		}
+		if issue.ClaimActive(now) && issue.ClaimedBy != actor {
+			return Issue{}, fmt.Errorf("issue %s is claimed by %s until %s", issue.ID, issue.ClaimedBy, issue.ClaimExpiresAt)
+		}
+		if issue.DeferActive(now.Local()) {
+			return Issue{}, fmt.Errorf("issue %s is deferred until %s (see pb undefer)", issue.ID, issue.DeferredUntil)
+		}
+		return issue, nil
+	}
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		return Issue{}, err
+	}
+	if len(ready) == 0 {
+		return Issue{}, ErrNothingToClaim
+	}
+	// ListReadyIssues already returns the queue in work order.
+	return ready[0], nil
+}
+
+// ReleaseIssue gives back a claim early. Releasing another actor's active
+// claim requires force; releasing an unclaimed issue does nothing.
+func ReleaseIssue(root, id, actor string, force bool) error {
+	return WithLock(root, func() error {
+		issue, _, err := GetIssue(root, id)
+		if err != nil {
+			return err
+		}
+		if issue.ClaimedBy == "" {
+			return nil
+		}
+		if !force && issue.ClaimedBy != actor && issue.ClaimActive(time.Now().UTC()) {
+			return fmt.Errorf("issue %s is claimed by %s; use --force to release it", issue.ID, issue.ClaimedBy)
+		}
+		if err := AppendEvent(root, NewReleaseEvent(issue.ID, NowTimestamp())); err != nil {
+			return err
+		}
+		return RebuildCache(root)
+	})
+}
@@ -0,0 +1,107 @@
+package pebbles
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// setupClaimProject creates a project with two ready issues of different priority.
+func setupClaimProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-low", "Low", "", "task", "2024-01-01T00:00:00Z", 3),
+		NewCreateEvent("pb-high", "High", "", "task", "2024-01-01T00:01:00Z", 1),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	return root
+}
+
+// TestClaimIssuePicksByPriority verifies claims take the best ready issue once.
+func TestClaimIssuePicksByPriority(t *testing.T) {
+	root := setupClaimProject(t)
+	first, err := ClaimIssue(root, "", "a@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if first.ID != "pb-high" || first.ClaimedBy != "a@example.com" {
+		t.Fatalf("expected pb-high claimed by a, got %s by %s", first.ID, first.ClaimedBy)
+	}
+	second, err := ClaimIssue(root, "", "b@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("second claim: %v", err)
+	}
+	if second.ID != "pb-low" {
+		t.Fatalf("expected pb-low for the second claim, got %s", second.ID)
+	}
+	if _, err := ClaimIssue(root, "", "c@example.com", time.Hour); !errors.Is(err, ErrNothingToClaim) {
+		t.Fatalf("expected ErrNothingToClaim, got %v", err)
+	}
+	if _, err := ClaimIssue(root, "pb-high", "c@example.com", time.Hour); err == nil {
+		t.Fatalf("expected claiming another actor's issue to fail")
+	}
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 0 {
+		t.Fatalf("expected claimed issues to leave ready, got %d", len(ready))
+	}
+}
+
+// TestExpiredClaimIsReadyAgain verifies expired leases return issues to ready.
+func TestExpiredClaimIsReadyAgain(t *testing.T) {
+	root := setupClaimProject(t)
+	claim := NewClaimEvent("pb-high", "2024-01-01T01:00:00Z", "2024-01-01T00:30:00Z")
+	claim.Actor = "gone@example.com"
+	if err := AppendEvent(root, claim); err != nil {
+		t.Fatalf("append claim: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 2 {
+		t.Fatalf("expected the expired claim to be ready again, got %d issues", len(ready))
+	}
+	claimed, err := ClaimIssue(root, "", "b@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("claim after expiry: %v", err)
+	}
+	if claimed.ID != "pb-high" || claimed.ClaimedBy != "b@example.com" {
+		t.Fatalf("expected pb-high reclaimed by b, got %s by %s", claimed.ID, claimed.ClaimedBy)
+	}
+}
+
+// TestReleaseIssue verifies only the claimant (or force) can release a claim.
+func TestReleaseIssue(t *testing.T) {
+	root := setupClaimProject(t)
+	if _, err := ClaimIssue(root, "pb-low", "a@example.com", time.Hour); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if err := ReleaseIssue(root, "pb-low", "b@example.com", false); err == nil {
+		t.Fatalf("expected release by another actor to fail")
+	}
+	if err := ReleaseIssue(root, "pb-low", "a@example.com", false); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-low")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.ClaimedBy != "" || issue.ClaimExpiresAt != "" {
+		t.Fatalf("expected claim cleared, got %q until %q", issue.ClaimedBy, issue.ClaimExpiresAt)
+	}
+}
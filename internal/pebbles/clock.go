@@ -0,0 +1,121 @@
+package pebbles
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// replayKey holds the values used to order one event for replay.
+type replayKey struct {
+	Index     int
+	Clock     int64
+	Legacy    bool
+	Time      time.Time
+	TimeOK    bool
+	Canonical string
+}
+
+// replayOrder returns event indexes in causal replay order.
+//
+// Events carry a Lamport clock stamped at append time, so they replay in clock
+// order. Concurrent events (equal clocks, e.g. from two branches) are broken by
+// timestamp, issue ID, type, and finally the canonical JSON encoding, which
+// gives the same order on every machine regardless of file order.
+//
+// Events written before clocks existed inherit the highest clock seen earlier
+// in the log (baseClock for the first lines), replay after clocked events with
+// that clock, and fall back to the legacy type-priority rule among themselves.
+func replayOrder(events []Event, baseClock int64) []int {
+	keys := make([]replayKey, len(events))
+	running := baseClock
+	for i, event := range events {
+		key := replayKey{Index: i, Clock: event.Clock}
+		if event.Clock <= 0 {
+			key.Clock = running
+			key.Legacy = true
+		} else {
+			if event.Clock > running {
+				running = event.Clock
+			}
+			key.Time, key.TimeOK = parseReplayTime(event.Timestamp)
+			key.Canonical = canonicalEvent(event)
+		}
+		keys[i] = key
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		left, right := keys[i], keys[j]
+		if left.Clock != right.Clock {
+			return left.Clock < right.Clock
+		}
+		if left.Legacy != right.Legacy {
+			return !left.Legacy
+		}
+		if left.Legacy {
+			return legacyEventLess(events[left.Index], events[right.Index])
+		}
+		return concurrentEventLess(events[left.Index], events[right.Index], left, right)
+	})
+	order := make([]int, len(keys))
+	for i, key := range keys {
+		order[i] = key.Index
+	}
+	return order
+}
+
+// concurrentEventLess deterministically orders two events with the same clock.
+func concurrentEventLess(a, b Event, keyA, keyB replayKey) bool {
+	if keyA.TimeOK && keyB.TimeOK && !keyA.Time.Equal(keyB.Time) {
+		return keyA.Time.Before(keyB.Time)
+	}
+	if a.IssueID != b.IssueID {
+		return a.IssueID < b.IssueID
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return keyA.Canonical < keyB.Canonical
+}
+
+// parseReplayTime parses an event timestamp for tie-breaking.
+func parseReplayTime(timestamp string) (time.Time, bool) {
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	return parsed, err == nil
+}
+
+// canonicalEvent returns a stable JSON encoding of an event.
+func canonicalEvent(event Event) string {
+	// encoding/json sorts map keys, so equal events encode identically.
+	data, err := json.Marshal(event)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// maxEventClock returns the highest clock carried by any event.
+func maxEventClock(events []Event) int64 {
+	var highest int64
+	for _, event := range events {
+		if event.Clock > highest {
+			highest = event.Clock
+		}
+	}
+	return highest
+}
+
+// minEventClock returns the lowest clock carried by a clocked event, if any.
+func minEventClock(events []Event) (int64, bool) {
+	var lowest int64
+	found := false
+	for _, event := range events {
+		if event.Clock <= 0 {
+			continue
+		}
+		if !found || event.Clock < lowest {
+			lowest = event.Clock
+			found = true
+		}
+	}
+	return lowest, found
+}
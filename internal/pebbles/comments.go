@@ -1,11 +1,40 @@
 package pebbles
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
-// ListIssueComments returns comment events for an issue in append order.
+// commentIDLength is the number of hex characters in a comment ID.
+const commentIDLength = 16
+
+// newCommentID returns a random comment ID for a new comment event.
+func newCommentID() string {
+	var buf [commentIDLength / 2]byte
+	_, _ = rand.Read(buf[:])
+	return "c-" + hex.EncodeToString(buf[:])
+}
+
+// CommentID returns the stable ID of the comment a comment event creates.
+// New events carry it in their comment_id payload. Comment events written
+// before comments had IDs derive one by hashing the event as written,
+// including its actor and clock, so it survives issue renames and replays.
+func CommentID(event Event) string {
+	if id := strings.TrimSpace(event.Payload["comment_id"]); id != "" {
+		return id
+	}
+	key := strings.Join([]string{event.IssueID, event.Timestamp, event.Actor, strconv.FormatInt(event.Clock, 10), event.Payload["body"]}, ":")
+	hash := sha256.Sum256([]byte(key))
+	return "c-" + hex.EncodeToString(hash[:])[:commentIDLength]
+}
+
+// ListIssueComments returns the live comments on an issue in replay order.
 func ListIssueComments(root, id string) ([]IssueComment, error) {
 	if err := EnsureCache(root); err != nil {
 		return nil, err
@@ -15,50 +44,149 @@ func ListIssueComments(root, id string) ([]IssueComment, error) {
 		return nil, err
 	}
 	defer func() { _ = db.Close() }()
-	// Resolve the requested ID before comparing against log entries.
+	// Resolve the requested ID before matching comment rows.
 	resolvedID, err := resolveIssueID(db, id)
 	if err != nil {
 		return nil, err
 	}
-	events, err := LoadEvents(root)
+	rows, err := db.Query(
+		"SELECT "+commentColumns+" FROM comments WHERE issue_id = ? AND deleted_at = '' ORDER BY rowid",
+		resolvedID,
+	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("list comments: %w", err)
 	}
 	comments := make([]IssueComment, 0)
-	for _, event := range events {
-		if event.Type != EventTypeComment {
-			continue
-		}
-		comment, ok, err := commentFromEvent(db, resolvedID, event)
+	for rows.Next() {
+		comment, err := scanComment(rows)
 		if err != nil {
+			_ = rows.Close()
 			return nil, err
 		}
-		if ok {
-			comments = append(comments, comment)
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("comment rows: %w", err)
+	}
+	_ = rows.Close()
+	// Attach edit history once the comment rows are released.
+	for i := range comments {
+		history, err := loadCommentHistory(db, comments[i].ID)
+		if err != nil {
+			return nil, err
 		}
+		comments[i].History = history
 	}
 	return comments, nil
 }
 
-// commentFromEvent builds a comment from an event if it matches the issue.
-func commentFromEvent(db *sql.DB, resolvedID string, event Event) (IssueComment, bool, error) {
-	resolvedEventID, err := resolveIssueID(db, event.IssueID)
+// GetComment returns a live comment by ID, including its issue ID.
+func GetComment(root, commentID string) (IssueComment, error) {
+	if err := EnsureCache(root); err != nil {
+		return IssueComment{}, err
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		return IssueComment{}, err
+	}
+	defer func() { _ = db.Close() }()
+	resolvedID, err := resolveCommentID(db, strings.TrimSpace(commentID))
+	if err != nil {
+		return IssueComment{}, err
+	}
+	comment, deleted, err := getCommentByID(db, resolvedID)
+	if err != nil {
+		return IssueComment{}, err
+	}
+	if deleted {
+		return IssueComment{}, fmt.Errorf("comment %s is deleted", comment.ID)
+	}
+	history, err := loadCommentHistory(db, comment.ID)
+	if err != nil {
+		return IssueComment{}, err
+	}
+	comment.History = history
+	return comment, nil
+}
+
+// commentColumns is the comment select list, in the order scanComment expects.
+const commentColumns = "id, issue_id, body, timestamp, actor, edited_at, edited_by"
+
+// scanComment scans a comment row selected with commentColumns.
+func scanComment(scanner interface{ Scan(...any) error }) (IssueComment, error) {
+	var comment IssueComment
+	if err := scanner.Scan(&comment.ID, &comment.IssueID, &comment.Body, &comment.Timestamp, &comment.Actor, &comment.EditedAt, &comment.EditedBy); err != nil {
+		return IssueComment{}, fmt.Errorf("scan comment: %w", err)
+	}
+	return comment, nil
+}
+
+// resolveCommentID matches a full comment ID or a unique prefix of one, so
+// short IDs typed at the CLI resolve. Replay never uses it: events always
+// store the full ID, which must match exactly.
+func resolveCommentID(db sqlExecutor, commentID string) (string, error) {
+	rows, err := db.Query("SELECT id FROM comments WHERE substr(id, 1, length(?)) = ? ORDER BY id LIMIT 2", commentID, commentID)
+	if err != nil {
+		return "", fmt.Errorf("resolve comment: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var matches []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return "", fmt.Errorf("scan comment id: %w", err)
+		}
+		matches = append(matches, id)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("resolve comment rows: %w", err)
+	}
+	switch {
+	case len(matches) == 0 || commentID == "":
+		return "", fmt.Errorf("comment not found: %s", commentID)
+	case len(matches) > 1 && matches[0] != commentID:
+		return "", fmt.Errorf("comment id %s is ambiguous; use more characters", commentID)
+	}
+	return matches[0], nil
+}
+
+// getCommentByID loads a comment row by its full ID and reports whether it
+// was deleted.
+func getCommentByID(db sqlExecutor, commentID string) (IssueComment, bool, error) {
+	var deletedAt string
+	row := db.QueryRow("SELECT "+commentColumns+", deleted_at FROM comments WHERE id = ?", commentID)
+	var comment IssueComment
+	err := row.Scan(&comment.ID, &comment.IssueID, &comment.Body, &comment.Timestamp, &comment.Actor, &comment.EditedAt, &comment.EditedBy, &deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return IssueComment{}, false, fmt.Errorf("comment not found: %s", commentID)
+	}
 	if err != nil {
-		return IssueComment{}, false, err
+		return IssueComment{}, false, fmt.Errorf("get comment: %w", err)
 	}
-	if resolvedEventID != resolvedID {
-		return IssueComment{}, false, nil
+	return comment, deletedAt != "", nil
+}
+
+// loadCommentHistory returns the earlier bodies of a comment, oldest first.
+func loadCommentHistory(db *sql.DB, commentID string) ([]CommentRevision, error) {
+	rows, err := db.Query(
+		"SELECT body, timestamp, actor FROM comment_history WHERE comment_id = ? ORDER BY rowid",
+		commentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("comment history: %w", err)
 	}
-	body := ""
-	if event.Payload != nil {
-		body = event.Payload["body"]
+	defer func() { _ = rows.Close() }()
+	var history []CommentRevision
+	for rows.Next() {
+		var revision CommentRevision
+		if err := rows.Scan(&revision.Body, &revision.Timestamp, &revision.Actor); err != nil {
+			return nil, fmt.Errorf("scan comment history: %w", err)
+		}
+		history = append(history, revision)
 	}
-	if body == "" {
-		return IssueComment{}, false, fmt.Errorf("comment event missing body for %s", resolvedEventID)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("comment history rows: %w", err)
 	}
-	return IssueComment{
-		IssueID:   resolvedEventID,
-		Body:      body,
-		Timestamp: event.Timestamp,
-	}, true, nil
+	return history, nil
 }
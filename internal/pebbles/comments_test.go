@@ -0,0 +1,152 @@
+package pebbles
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCommentEditAndDeleteKeepHistory(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	t.Setenv(ActorEnvVar, "alice@example.com")
+	first := NewCommentEvent("pb-old", "Frist note", "2024-01-01T00:01:00Z")
+	second := NewCommentEvent("pb-old", "Second note", "2024-01-01T00:02:00Z")
+	firstID := CommentID(first)
+	if !strings.HasPrefix(firstID, "c-") || len(firstID) != 2+commentIDLength {
+		t.Fatalf("unexpected comment id %q", firstID)
+	}
+	if firstID == CommentID(second) {
+		t.Fatalf("expected distinct comment ids")
+	}
+	events := []Event{
+		NewCreateEvent("pb-old", "Commented", "", "task", "2024-01-01T00:00:00Z", 2),
+		first,
+		second,
+		// Comment IDs come from the original event, so they survive renames.
+		NewRenameEvent("pb-old", "pb-new", "2024-01-01T00:03:00Z"),
+		NewCommentEditEvent("pb-new", firstID, "First note", "2024-01-01T00:04:00Z"),
+		NewCommentDeleteEvent("pb-new", CommentID(second), "2024-01-01T00:05:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	comments, err := ListIssueComments(root, "pb-old")
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected deleted comment hidden, got %+v", comments)
+	}
+	comment := comments[0]
+	if comment.ID != firstID || comment.IssueID != "pb-new" || comment.Body != "First note" {
+		t.Fatalf("unexpected comment: %+v", comment)
+	}
+	if comment.Timestamp != "2024-01-01T00:01:00Z" || comment.EditedAt != "2024-01-01T00:04:00Z" {
+		t.Fatalf("unexpected comment timestamps: %+v", comment)
+	}
+	if len(comment.History) != 1 || comment.History[0].Body != "Frist note" || comment.History[0].Actor != "alice@example.com" {
+		t.Fatalf("unexpected history: %+v", comment.History)
+	}
+	if _, err := GetComment(root, CommentID(second)); err == nil || !strings.Contains(err.Error(), "deleted") {
+		t.Fatalf("expected deleted comment error, got %v", err)
+	}
+}
+
+func TestCommentIDsStayDistinctAndResolveExactly(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	// Two agents posting the same text at the same moment get two comments.
+	first := NewCommentEvent("pb-1", "Same", "2024-01-01T00:01:00Z")
+	second := NewCommentEvent("pb-1", "Same", "2024-01-01T00:01:00Z")
+	second.Actor = "bob@example.com"
+	if CommentID(first) == CommentID(second) {
+		t.Fatalf("expected distinct ids for concurrent identical comments")
+	}
+	// Comments written before events carried IDs hash their actor and clock.
+	legacy := []string{
+		`{"type":"comment","timestamp":"2024-01-01T00:02:00Z","issue_id":"pb-1","actor":"alice@example.com","payload":{"body":"Old"}}`,
+		`{"type":"comment","timestamp":"2024-01-01T00:02:00Z","issue_id":"pb-1","actor":"bob@example.com","payload":{"body":"Old"}}`,
+	}
+	events := []Event{
+		NewCreateEvent("pb-1", "Commented", "", "task", "2024-01-01T00:00:00Z", 2),
+		first,
+		second,
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	file, err := os.OpenFile(EventsPath(root), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("open events log: %v", err)
+	}
+	if _, err := file.WriteString(strings.Join(legacy, "\n") + "\n"); err != nil {
+		t.Fatalf("append legacy comments: %v", err)
+	}
+	_ = file.Close()
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	comments, err := ListIssueComments(root, "pb-1")
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if len(comments) != 4 {
+		t.Fatalf("expected every comment kept, got %+v", comments)
+	}
+	// The CLI accepts a unique prefix and resolves it to the full ID.
+	shortID := CommentID(first)[:len("c-")+8]
+	found, err := GetComment(root, shortID)
+	if err != nil || found.ID != CommentID(first) {
+		t.Fatalf("expected the short id to resolve, got %+v (%v)", found, err)
+	}
+	if _, err := GetComment(root, "c-"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("expected a prefix shared by several comments to be ambiguous, got %v", err)
+	}
+	// Replay only matches full IDs, so a prefix in the log never binds to
+	// whichever comment happens to be unique.
+	if err := AppendEvent(root, NewCommentEditEvent("pb-1", shortID, "Edited", "2024-01-01T00:03:00Z")); err != nil {
+		t.Fatalf("append edit: %v", err)
+	}
+	if err := RebuildCache(root); err == nil || !strings.Contains(err.Error(), "comment not found") {
+		t.Fatalf("expected replay to reject a prefix comment id, got %v", err)
+	}
+	report, err := CheckEventLog(root)
+	if err != nil {
+		t.Fatalf("check event log: %v", err)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].Code != ProblemMissingComment {
+		t.Fatalf("expected doctor to report the prefix id, got %+v", report.Problems)
+	}
+}
+
+func TestCheckEventLogReportsMissingComment(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	comment := Event{Type: EventTypeComment, Timestamp: "2024-01-01T00:00:01Z", IssueID: "pb-1", Payload: map[string]string{"body": "Note"}}
+	lines := []string{
+		`{"type":"create","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"title":"First"}}`,
+		`{"type":"comment","timestamp":"2024-01-01T00:00:01Z","issue_id":"pb-1","payload":{"body":"Note"}}`,
+		`{"type":"comment_delete","timestamp":"2024-01-01T00:00:02Z","issue_id":"pb-1","payload":{"comment_id":"` + CommentID(comment) + `"}}`,
+		`{"type":"comment_edit","timestamp":"2024-01-01T00:00:03Z","issue_id":"pb-1","payload":{"comment_id":"` + CommentID(comment) + `","body":"Late"}}`,
+	}
+	if err := os.WriteFile(EventsPath(root), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	report, err := CheckEventLog(root)
+	if err != nil {
+		t.Fatalf("check event log: %v", err)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].Line != 4 || report.Problems[0].Code != ProblemMissingComment {
+		t.Fatalf("expected missing comment on line 4, got %+v", report.Problems)
+	}
+}
@@ -0,0 +1,224 @@
+package pebbles
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConflictMarkerError reports git merge conflict markers in the event log.
+type ConflictMarkerError struct {
+	Line int
+}
+
+// Error describes the conflict and how to recover from it.
+func (err *ConflictMarkerError) Error() string {
+	return fmt.Sprintf("events log has git merge conflict markers at line %d; run pb resolve", err.Line)
+}
+
+// ConflictBlock summarizes how one conflict block was resolved.
+type ConflictBlock struct {
+	StartLine  int
+	EndLine    int
+	Ours       int
+	Theirs     int
+	Base       int
+	Duplicates int
+	Kept       int
+}
+
+// ConflictResolution summarizes a conflict marker cleanup of the event log.
+type ConflictResolution struct {
+	Blocks  []ConflictBlock
+	Written bool
+}
+
+// conflictSide tracks which part of a conflict block a line belongs to.
+type conflictSide int
+
+const (
+	conflictNone conflictSide = iota
+	conflictOurs
+	conflictBase
+	conflictTheirs
+)
+
+// isConflictMarker reports whether a trimmed log line is a git conflict marker.
+func isConflictMarker(line []byte) bool {
+	return conflictMarkerSide(line) != conflictNone || isConflictEnd(line)
+}
+
+// conflictMarkerSide returns the side a marker line opens, if any.
+func conflictMarkerSide(line []byte) conflictSide {
+	switch {
+	case hasMarkerPrefix(line, "<<<<<<<"):
+		return conflictOurs
+	case hasMarkerPrefix(line, "|||||||"):
+		return conflictBase
+	case bytes.Equal(line, []byte("=======")):
+		return conflictTheirs
+	}
+	return conflictNone
+}
+
+// isConflictEnd reports whether a line closes a conflict block.
+func isConflictEnd(line []byte) bool {
+	return hasMarkerPrefix(line, ">>>>>>>")
+}
+
+// hasMarkerPrefix matches a marker followed by nothing or a space and label.
+func hasMarkerPrefix(line []byte, marker string) bool {
+	if !bytes.HasPrefix(line, []byte(marker)) {
+		return false
+	}
+	rest := line[len(marker):]
+	return len(rest) == 0 || rest[0] == ' '
+}
+
+// ResolveConflicts rewrites an event log containing git conflict markers,
+// keeping the event lines from both sides of each block. Lines from "ours"
+// come first, then "theirs", each in file order; blank lines and exact
+// duplicates of lines already in the log are dropped. The diff3 base section,
+// if present, is the common ancestor rather than either side and is dropped.
+// When dryRun is set the log is left untouched.
+func ResolveConflicts(root string, dryRun bool) (ConflictResolution, error) {
+	var resolution ConflictResolution
+	err := WithLock(root, func() error {
+		path := EventsPath(root)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read events log: %w", err)
+		}
+		resolved, blocks, err := resolveConflictData(data)
+		if err != nil {
+			return err
+		}
+		resolution.Blocks = blocks
+		if len(blocks) == 0 || dryRun {
+			return nil
+		}
+		if err := replaceEventLog(path, resolved); err != nil {
+			return err
+		}
+		resolution.Written = true
+		// The rewritten prefix no longer matches the checkpoint, so this replays
+		// the full log.
+		return RebuildCache(root)
+	})
+	if err != nil {
+		return ConflictResolution{}, err
+	}
+	return resolution, nil
+}
+
+// resolveConflictData merges conflict blocks in log data and returns the result.
+func resolveConflictData(data []byte) ([]byte, []ConflictBlock, error) {
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	// Lines outside conflict blocks always survive; seed the duplicate check with them.
+	seen := make(map[string]bool)
+	side := conflictNone
+	for _, raw := range lines {
+		line := bytes.TrimSpace(raw)
+		switch {
+		case conflictMarkerSide(line) == conflictOurs:
+			side = conflictOurs
+		case isConflictEnd(line):
+			side = conflictNone
+		case side == conflictNone && len(line) > 0:
+			seen[string(line)] = true
+		}
+	}
+	var output bytes.Buffer
+	var blocks []ConflictBlock
+	var block ConflictBlock
+	var ours, theirs [][]byte
+	side = conflictNone
+	for index, raw := range lines {
+		lineNumber := index + 1
+		line := bytes.TrimSpace(raw)
+		markerSide := conflictMarkerSide(line)
+		switch {
+		case markerSide == conflictOurs:
+			if side != conflictNone {
+				return nil, nil, fmt.Errorf("nested conflict marker at line %d", lineNumber)
+			}
+			side = conflictOurs
+			block = ConflictBlock{StartLine: lineNumber}
+			ours, theirs = nil, nil
+		case markerSide != conflictNone:
+			if side == conflictNone {
+				return nil, nil, fmt.Errorf("conflict marker outside a conflict block at line %d", lineNumber)
+			}
+			side = markerSide
+		case isConflictEnd(line):
+			if side == conflictNone {
+				return nil, nil, fmt.Errorf("conflict marker outside a conflict block at line %d", lineNumber)
+			}
+			block.EndLine = lineNumber
+			// Keep both sides in a stable order: ours first, then theirs.
+			for _, kept := range append(ours, theirs...) {
+				if seen[string(kept)] {
+					block.Duplicates++
+					continue
+				}
+				seen[string(kept)] = true
+				output.Write(kept)
+				output.WriteByte('\n')
+				block.Kept++
+			}
+			blocks = append(blocks, block)
+			side = conflictNone
+		case side == conflictNone:
+			// Lines outside conflict blocks are copied verbatim.
+			output.Write(raw)
+			output.WriteByte('\n')
+		case len(line) == 0:
+			continue
+		case side == conflictOurs:
+			ours = append(ours, line)
+			block.Ours++
+		case side == conflictBase:
+			block.Base++
+		case side == conflictTheirs:
+			theirs = append(theirs, line)
+			block.Theirs++
+		}
+	}
+	if side != conflictNone {
+		return nil, nil, fmt.Errorf("unterminated conflict block starting at line %d", block.StartLine)
+	}
+	return output.Bytes(), blocks, nil
+}
+
+// replaceEventLog atomically swaps the event log contents via a temp file.
+func replaceEventLog(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat events log: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), ".events-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("create temp events log: %w", err)
+	}
+	tempPath := temp.Name()
+	defer func() { _ = os.Remove(tempPath) }()
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return fmt.Errorf("write temp events log: %w", err)
+	}
+	if err := temp.Sync(); err != nil {
+		_ = temp.Close()
+		return fmt.Errorf("sync temp events log: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("close temp events log: %w", err)
+	}
+	if err := os.Chmod(tempPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("chmod temp events log: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("replace events log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package pebbles
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadEventsReportsConflictMarkers(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	writeConflictedLog(t, root)
+	_, err := LoadEvents(root)
+	var conflictErr *ConflictMarkerError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected conflict marker error, got %v", err)
+	}
+	if conflictErr.Line != 2 {
+		t.Fatalf("expected conflict at line 2, got %d", conflictErr.Line)
+	}
+	if !strings.Contains(err.Error(), "pb resolve") {
+		t.Fatalf("expected pb resolve hint, got %q", err.Error())
+	}
+}
+
+func TestResolveConflictsKeepsBothSides(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	writeConflictedLog(t, root)
+	resolution, err := ResolveConflicts(root, false)
+	if err != nil {
+		t.Fatalf("resolve conflicts: %v", err)
+	}
+	if len(resolution.Blocks) != 1 || !resolution.Written {
+		t.Fatalf("expected one written block, got %+v", resolution)
+	}
+	block := resolution.Blocks[0]
+	if block.Ours != 2 || block.Theirs != 2 || block.Duplicates != 1 || block.Kept != 3 || block.Base != 1 {
+		t.Fatalf("unexpected block summary: %+v", block)
+	}
+	events, err := LoadEvents(root)
+	if err != nil {
+		t.Fatalf("load resolved events: %v", err)
+	}
+	// Ours come first, then theirs, with the shared comment kept once.
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.Type+":"+event.IssueID)
+	}
+	want := "create:pb-1,create:pb-2,comment:pb-1,create:pb-3"
+	if strings.Join(ids, ",") != want {
+		t.Fatalf("expected %s, got %s", want, strings.Join(ids, ","))
+	}
+	issues, err := ListIssues(root)
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues after resolve, got %d", len(issues))
+	}
+}
+
+func TestResolveConflictsDryRunLeavesLog(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	before := writeConflictedLog(t, root)
+	resolution, err := ResolveConflicts(root, true)
+	if err != nil {
+		t.Fatalf("resolve conflicts: %v", err)
+	}
+	if len(resolution.Blocks) != 1 || resolution.Written {
+		t.Fatalf("expected an unwritten block, got %+v", resolution)
+	}
+	after, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		t.Fatalf("read events log: %v", err)
+	}
+	if string(after) != before {
+		t.Fatalf("expected dry run to leave the log unchanged")
+	}
+}
+
+// writeConflictedLog writes a log with one diff3-style conflict block.
+func writeConflictedLog(t *testing.T, root string) string {
+	t.Helper()
+	comment := `{"type":"comment","timestamp":"2024-01-01T00:00:03Z","issue_id":"pb-1","payload":{"body":"shared"}}`
+	lines := []string{
+		`{"type":"create","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"title":"Base"}}`,
+		"<<<<<<< HEAD",
+		`{"type":"create","timestamp":"2024-01-01T00:00:01Z","issue_id":"pb-2","payload":{"title":"Ours"}}`,
+		comment,
+		"||||||| merged common ancestors",
+		`{"type":"comment","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"body":"base"}}`,
+		"=======",
+		comment,
+		`{"type":"create","timestamp":"2024-01-01T00:00:02Z","issue_id":"pb-3","payload":{"title":"Theirs"}}`,
+		">>>>>>> feature",
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(EventsPath(root), []byte(content), 0600); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	return content
+}
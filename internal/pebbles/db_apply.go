@@ -3,15 +3,25 @@ package pebbles
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
-// resetSchema drops the issue and dependency tables.
-func resetSchema(db *sql.DB) error {
+// resetSchema drops the issue, dependency, label, comment, search, skipped
+// event, and metadata tables.
+func resetSchema(db sqlExecutor) error {
 	queries := []string{
+		"DROP TABLE IF EXISTS cache_meta",
+		"DROP TABLE IF EXISTS comment_history",
+		"DROP TABLE IF EXISTS comments",
 		"DROP TABLE IF EXISTS deps",
+		"DROP TABLE IF EXISTS detached_deps",
+		"DROP TABLE IF EXISTS fields",
+		"DROP TABLE IF EXISTS issue_search",
 		"DROP TABLE IF EXISTS issues",
+		"DROP TABLE IF EXISTS labels",
 		"DROP TABLE IF EXISTS renames",
+		"DROP TABLE IF EXISTS skipped_events",
 	}
 	for _, query := range queries {
 		if _, err := db.Exec(query); err != nil {
@@ -21,8 +31,8 @@ func resetSchema(db *sql.DB) error {
 	return nil
 }
 
-// ensureSchema creates the issue and dependency tables.
-func ensureSchema(db *sql.DB) error {
+// ensureSchema creates the issue, dependency, label, skipped event, and metadata tables.
+func ensureSchema(db sqlExecutor) error {
 	queries := []string{
 		`CREATE TABLE IF NOT EXISTS issues (
 			id TEXT PRIMARY KEY,
@@ -33,7 +43,20 @@ func ensureSchema(db *sql.DB) error {
 			priority INTEGER NOT NULL,
 			created_at TEXT NOT NULL,
 			updated_at TEXT NOT NULL,
-			closed_at TEXT
+			closed_at TEXT,
+			resolution TEXT NOT NULL DEFAULT '',
+			close_reason TEXT NOT NULL DEFAULT '',
+			assignee TEXT NOT NULL DEFAULT '',
+			claimed_by TEXT NOT NULL DEFAULT '',
+			claim_expires_at TEXT NOT NULL DEFAULT '',
+			deleted_at TEXT NOT NULL DEFAULT '',
+			delete_reason TEXT NOT NULL DEFAULT '',
+			due_at TEXT NOT NULL DEFAULT '',
+			deferred_until TEXT NOT NULL DEFAULT '',
+			estimate TEXT NOT NULL DEFAULT '',
+			logged_minutes INTEGER NOT NULL DEFAULT 0,
+			timer_started_at TEXT NOT NULL DEFAULT '',
+			timer_started_by TEXT NOT NULL DEFAULT ''
 		)`,
 		`CREATE TABLE IF NOT EXISTS deps (
 			issue_id TEXT NOT NULL,
@@ -41,10 +64,62 @@ func ensureSchema(db *sql.DB) error {
 			dep_type TEXT NOT NULL,
 			PRIMARY KEY (issue_id, depends_on_id, dep_type)
 		)`,
+		`CREATE TABLE IF NOT EXISTS detached_deps (
+			issue_id TEXT NOT NULL,
+			depends_on_id TEXT NOT NULL,
+			dep_type TEXT NOT NULL,
+			deleted_id TEXT NOT NULL,
+			PRIMARY KEY (issue_id, depends_on_id, dep_type)
+		)`,
+		`CREATE TABLE IF NOT EXISTS labels (
+			issue_id TEXT NOT NULL,
+			label TEXT NOT NULL,
+			PRIMARY KEY (issue_id, label)
+		)`,
+		`CREATE TABLE IF NOT EXISTS fields (
+			issue_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (issue_id, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS comments (
+			id TEXT PRIMARY KEY,
+			issue_id TEXT NOT NULL,
+			body TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			actor TEXT NOT NULL,
+			edited_at TEXT NOT NULL DEFAULT '',
+			edited_by TEXT NOT NULL DEFAULT '',
+			deleted_at TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE TABLE IF NOT EXISTS comment_history (
+			comment_id TEXT NOT NULL,
+			body TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			actor TEXT NOT NULL
+		)`,
 		`CREATE TABLE IF NOT EXISTS renames (
 			old_id TEXT PRIMARY KEY,
 			new_id TEXT NOT NULL
 		)`,
+		`CREATE TABLE IF NOT EXISTS skipped_events (
+			event_type TEXT NOT NULL,
+			issue_id TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			reason TEXT NOT NULL
+		)`,
+		`CREATE VIRTUAL TABLE IF NOT EXISTS issue_search USING fts5(
+			issue_id UNINDEXED,
+			title,
+			description,
+			comments,
+			tokenize = 'porter unicode61'
+		)`,
+		`CREATE TABLE IF NOT EXISTS cache_meta (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`,
 	}
 	// Execute each schema statement in order.
 	for _, query := range queries {
@@ -56,7 +131,7 @@ func ensureSchema(db *sql.DB) error {
 }
 
 // applyEvents replays events into the SQLite cache.
-func applyEvents(db *sql.DB, events []Event) error {
+func applyEvents(db sqlExecutor, events []Event) error {
 	for _, event := range events {
 		if err := applyEvent(db, event); err != nil {
 			return err
@@ -65,8 +140,25 @@ func applyEvents(db *sql.DB, events []Event) error {
 	return nil
 }
 
-// applyEvent applies a single event into the SQLite cache.
-func applyEvent(db *sql.DB, event Event) error {
+// applyEvent validates an event's payload against eventSpecs and applies it
+// into the SQLite cache. Unknown event types, and events from a newer schema that fail to apply, are
+// recorded as skipped so older binaries keep working against newer logs.
+func applyEvent(db sqlExecutor, event Event) error {
+	if !IsKnownEventType(event.Type) {
+		return recordSkippedEvent(db, event, fmt.Sprintf("unknown event type %q", event.Type))
+	}
+	err := validateEventPayload(event)
+	if err == nil {
+		err = applyKnownEvent(db, event)
+	}
+	if err != nil && event.Version > EventSchemaVersion {
+		return recordSkippedEvent(db, event, err.Error())
+	}
+	return err
+}
+
+// applyKnownEvent dispatches an event of a recognised type.
+func applyKnownEvent(db sqlExecutor, event Event) error {
 	switch event.Type {
 	case EventTypeCreate:
 		return applyCreate(db, event)
@@ -101,7 +193,19 @@ func applyEvent(db *sql.DB, event Event) error {
 		if err != nil {
 			return err
 		}
-		return applyComment(db, resolved)
+		return applyComment(db, resolved, CommentID(event))
+	case EventTypeCommentEdit:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyCommentEdit(db, resolved)
+	case EventTypeCommentDelete:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyCommentDelete(db, resolved)
 	case EventTypeDepAdd:
 		resolved, err := resolveEventDependencyIDs(db, event)
 		if err != nil {
@@ -114,16 +218,106 @@ func applyEvent(db *sql.DB, event Event) error {
 			return err
 		}
 		return applyDepRemove(db, resolved)
+	case EventTypeLabelAdd:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyLabelAdd(db, resolved)
+	case EventTypeLabelRemove:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyLabelRemove(db, resolved)
+	case EventTypeFieldSet:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyFieldSet(db, resolved)
+	case EventTypeDue:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyDue(db, resolved)
+	case EventTypeDefer, EventTypeUndefer:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyDefer(db, resolved)
+	case EventTypeEstimate:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyEstimate(db, resolved)
+	case EventTypeTimerStart:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyTimerStart(db, resolved)
+	case EventTypeWorkLog:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyWorkLog(db, resolved)
+	case EventTypeAssign:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyAssign(db, resolved)
+	case EventTypeClaim:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyClaim(db, resolved)
+	case EventTypeRelease:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyRelease(db, resolved)
+	case EventTypeDelete:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyDelete(db, resolved)
+	case EventTypeUndelete:
+		resolved, err := resolveEventIssueID(db, event)
+		if err != nil {
+			return err
+		}
+		return applyUndelete(db, resolved)
 	default:
 		return fmt.Errorf("unknown event type: %s", event.Type)
 	}
 }
 
-func applyTitleUpdated(db *sql.DB, event Event) error {
-	title := strings.TrimSpace(event.Payload["title"])
-	if title == "" {
-		return fmt.Errorf("title_updated event missing title")
+// recordSkippedEvent stores an event that replay could not apply.
+func recordSkippedEvent(db sqlExecutor, event Event, reason string) error {
+	if _, err := db.Exec(
+		"INSERT INTO skipped_events (event_type, issue_id, timestamp, version, reason) VALUES (?, ?, ?, ?, ?)",
+		event.Type,
+		event.IssueID,
+		event.Timestamp,
+		event.Version,
+		reason,
+	); err != nil {
+		return fmt.Errorf("record skipped event: %w", err)
 	}
+	return nil
+}
+
+// applyTitleUpdated sets an issue title from a title_updated event.
+func applyTitleUpdated(db sqlExecutor, event Event) error {
 	result, err := db.Exec(
 		"UPDATE issues SET title = ?, updated_at = ? WHERE id = ?",
 		event.Payload["title"],
@@ -137,7 +331,7 @@ func applyTitleUpdated(db *sql.DB, event Event) error {
 }
 
 // resolveEventIssueID returns a copy of the event with a resolved IssueID.
-func resolveEventIssueID(db *sql.DB, event Event) (Event, error) {
+func resolveEventIssueID(db sqlExecutor, event Event) (Event, error) {
 	resolvedID, err := resolveIssueID(db, event.IssueID)
 	if err != nil {
 		return Event{}, err
@@ -147,15 +341,12 @@ func resolveEventIssueID(db *sql.DB, event Event) (Event, error) {
 }
 
 // resolveEventDependencyIDs resolves dependency IDs to their current values.
-func resolveEventDependencyIDs(db *sql.DB, event Event) (Event, error) {
+func resolveEventDependencyIDs(db sqlExecutor, event Event) (Event, error) {
 	resolvedIssueID, err := resolveIssueID(db, event.IssueID)
 	if err != nil {
 		return Event{}, err
 	}
 	dependsOn := event.Payload["depends_on"]
-	if dependsOn == "" {
-		return Event{}, fmt.Errorf("dependency event missing depends_on")
-	}
 	depType := NormalizeDepType(event.Payload["dep_type"])
 	// Resolve the dependency target before rewriting the event payload.
 	resolvedDependsOn, err := resolveIssueID(db, dependsOn)
@@ -171,11 +362,8 @@ func resolveEventDependencyIDs(db *sql.DB, event Event) (Event, error) {
 }
 
 // applyCreate inserts a new issue from a create event.
-func applyCreate(db *sql.DB, event Event) error {
-	title, ok := event.Payload["title"]
-	if !ok || title == "" {
-		return fmt.Errorf("create event missing title")
-	}
+func applyCreate(db sqlExecutor, event Event) error {
+	title := event.Payload["title"]
 	description := event.Payload["description"]
 	// Default issue type to task when omitted.
 	issueType := event.Payload["type"]
@@ -203,11 +391,8 @@ func applyCreate(db *sql.DB, event Event) error {
 }
 
 // applyRename renames an issue ID and updates dependencies.
-func applyRename(db *sql.DB, event Event) error {
+func applyRename(db sqlExecutor, event Event) error {
 	newID := event.Payload["new_id"]
-	if newID == "" {
-		return fmt.Errorf("rename event missing new_id")
-	}
 	// Resolve the current issue ID and validate the target ID.
 	resolvedOldID, err := resolveIssueID(db, event.IssueID)
 	if err != nil {
@@ -236,6 +421,9 @@ func applyRename(db *sql.DB, event Event) error {
 	if err := updateDepsForRename(db, resolvedOldID, newID); err != nil {
 		return err
 	}
+	if err := moveIssueRowsForRename(db, resolvedOldID, newID); err != nil {
+		return err
+	}
 	if err := upsertRename(db, resolvedOldID, newID); err != nil {
 		return err
 	}
@@ -243,25 +431,28 @@ func applyRename(db *sql.DB, event Event) error {
 }
 
 // applyStatus updates an issue status from a status update event.
-func applyStatus(db *sql.DB, event Event) error {
+func applyStatus(db sqlExecutor, event Event) error {
 	status := event.Payload["status"]
-	if status == "" {
-		return fmt.Errorf("status event missing status")
-	}
+	// Replay cannot see the workflow, so the writer records the status
+	// category. Older events without one count only "closed" as done.
+	category := event.Payload["category"]
+	done := category == StatusCategoryDone || (category == "" && status == StatusClosed)
 	var result sql.Result
 	var err error
-	if status == StatusClosed {
-		// Apply the status update and touch updated_at.
+	if done {
+		// Entering a done status stamps closed_at; moving between done
+		// statuses keeps the first stamp and the close resolution.
 		result, err = db.Exec(
-			"UPDATE issues SET status = ?, updated_at = ? WHERE id = ?",
+			"UPDATE issues SET status = ?, updated_at = ?, closed_at = CASE WHEN closed_at = '' THEN ? ELSE closed_at END WHERE id = ?",
 			status,
 			event.Timestamp,
+			event.Timestamp,
 			event.IssueID,
 		)
 	} else {
-		// Reopening clears closed_at while updating status/updated_at.
+		// Reopening clears closed_at and the close resolution while updating status/updated_at.
 		result, err = db.Exec(
-			"UPDATE issues SET status = ?, updated_at = ?, closed_at = ? WHERE id = ?",
+			"UPDATE issues SET status = ?, updated_at = ?, closed_at = ?, resolution = '', close_reason = '' WHERE id = ?",
 			status,
 			event.Timestamp,
 			"",
@@ -275,7 +466,7 @@ func applyStatus(db *sql.DB, event Event) error {
 }
 
 // applyUpdate updates issue fields from an update event.
-func applyUpdate(db *sql.DB, event Event) error {
+func applyUpdate(db sqlExecutor, event Event) error {
 	var updates []string
 	var args []any
 	if issueType, ok := event.Payload["type"]; ok {
@@ -290,9 +481,6 @@ func applyUpdate(db *sql.DB, event Event) error {
 		updates = append(updates, "priority = ?")
 		args = append(args, parsePriority(priority))
 	}
-	if len(updates) == 0 {
-		return fmt.Errorf("update event missing fields")
-	}
 	updates = append(updates, "updated_at = ?")
 	args = append(args, event.Timestamp, event.IssueID)
 	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(updates, ", "))
@@ -304,13 +492,15 @@ func applyUpdate(db *sql.DB, event Event) error {
 }
 
 // applyClose closes an issue from a close event.
-func applyClose(db *sql.DB, event Event) error {
-	// Close the issue and stamp updated_at/closed_at.
+func applyClose(db sqlExecutor, event Event) error {
+	// Close the issue, stamp updated_at/closed_at, and record why it closed.
 	result, err := db.Exec(
-		"UPDATE issues SET status = ?, updated_at = ?, closed_at = ? WHERE id = ?",
+		"UPDATE issues SET status = ?, updated_at = ?, closed_at = ?, resolution = ?, close_reason = ? WHERE id = ?",
 		StatusClosed,
 		event.Timestamp,
 		event.Timestamp,
+		event.Payload["resolution"],
+		event.Payload["reason"],
 		event.IssueID,
 	)
 	if err != nil {
@@ -319,25 +509,93 @@ func applyClose(db *sql.DB, event Event) error {
 	return requireRow(result, "close for missing issue")
 }
 
-// applyComment validates comment events without mutating the cache.
-func applyComment(db *sql.DB, event Event) error {
-	body := strings.TrimSpace(event.Payload["body"])
-	if body == "" {
-		return fmt.Errorf("comment event missing body")
-	}
+// applyComment stores a comment under the ID derived from its original event.
+func applyComment(db sqlExecutor, event Event, commentID string) error {
+	body := event.Payload["body"]
 	// Comments don't mutate issue rows, but they must target an existing issue.
 	if err := ensureIssueExists(db, event.IssueID); err != nil {
 		return err
 	}
+	// A line duplicated by a union merge carries the same ID; keep the first.
+	if _, err := db.Exec(
+		"INSERT OR IGNORE INTO comments (id, issue_id, body, timestamp, actor) VALUES (?, ?, ?, ?, ?)",
+		commentID,
+		event.IssueID,
+		body,
+		event.Timestamp,
+		event.Actor,
+	); err != nil {
+		return fmt.Errorf("insert comment: %w", err)
+	}
 	return nil
 }
 
+// applyCommentEdit replaces a comment body, keeping the old body as history.
+func applyCommentEdit(db sqlExecutor, event Event) error {
+	body := event.Payload["body"]
+	comment, err := requireLiveComment(db, event)
+	if err != nil {
+		return err
+	}
+	// The replaced body was written at the last edit, or at creation.
+	revision := CommentRevision{Body: comment.Body, Timestamp: comment.Timestamp, Actor: comment.Actor}
+	if comment.EditedAt != "" {
+		revision.Timestamp = comment.EditedAt
+		revision.Actor = comment.EditedBy
+	}
+	if _, err := db.Exec(
+		"INSERT INTO comment_history (comment_id, body, timestamp, actor) VALUES (?, ?, ?, ?)",
+		comment.ID,
+		revision.Body,
+		revision.Timestamp,
+		revision.Actor,
+	); err != nil {
+		return fmt.Errorf("record comment history: %w", err)
+	}
+	if _, err := db.Exec(
+		"UPDATE comments SET body = ?, edited_at = ?, edited_by = ? WHERE id = ?",
+		body,
+		event.Timestamp,
+		event.Actor,
+		comment.ID,
+	); err != nil {
+		return fmt.Errorf("edit comment: %w", err)
+	}
+	return nil
+}
+
+// applyCommentDelete hides a comment; its row and history stay in the cache.
+func applyCommentDelete(db sqlExecutor, event Event) error {
+	comment, err := requireLiveComment(db, event)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("UPDATE comments SET deleted_at = ? WHERE id = ?", event.Timestamp, comment.ID); err != nil {
+		return fmt.Errorf("delete comment: %w", err)
+	}
+	return nil
+}
+
+// requireLiveComment loads the comment an edit or delete event targets and
+// checks it belongs to the event's issue and is not deleted.
+func requireLiveComment(db sqlExecutor, event Event) (IssueComment, error) {
+	commentID := strings.TrimSpace(event.Payload["comment_id"])
+	comment, deleted, err := getCommentByID(db, commentID)
+	if err != nil {
+		return IssueComment{}, err
+	}
+	if comment.IssueID != event.IssueID {
+		return IssueComment{}, fmt.Errorf("comment %s belongs to %s, not %s", commentID, comment.IssueID, event.IssueID)
+	}
+	if deleted {
+		return IssueComment{}, fmt.Errorf("comment %s is deleted", commentID)
+	}
+	return comment, nil
+}
+
 // applyDepAdd inserts a dependency from a dep_add event.
-func applyDepAdd(db *sql.DB, event Event) error {
+func applyDepAdd(db sqlExecutor, event Event) error {
 	dependsOn := event.Payload["depends_on"]
-	if dependsOn == "" {
-		return fmt.Errorf("dep_add event missing depends_on")
-	}
 	depType := NormalizeDepType(event.Payload["dep_type"])
 	// Validate both ends exist before writing the dependency.
 	if err := ensureIssueExists(db, event.IssueID); err != nil {
@@ -346,8 +604,26 @@ func applyDepAdd(db *sql.DB, event Event) error {
 	if err := ensureIssueExists(db, dependsOn); err != nil {
 		return err
 	}
+	// An edge touching a deleted issue is parked until that issue is restored.
+	deletedID, err := deletedEndpoint(db, event.IssueID, dependsOn)
+	if err != nil {
+		return err
+	}
+	if deletedID != "" {
+		_, err = db.Exec(
+			"INSERT OR IGNORE INTO detached_deps (issue_id, depends_on_id, dep_type, deleted_id) VALUES (?, ?, ?, ?)",
+			event.IssueID,
+			dependsOn,
+			depType,
+			deletedID,
+		)
+		if err != nil {
+			return fmt.Errorf("insert detached dependency: %w", err)
+		}
+		return nil
+	}
 	// Insert a dependency edge, ignoring duplicates.
-	_, err := db.Exec(
+	_, err = db.Exec(
 		"INSERT OR IGNORE INTO deps (issue_id, depends_on_id, dep_type) VALUES (?, ?, ?)",
 		event.IssueID,
 		dependsOn,
@@ -360,11 +636,8 @@ func applyDepAdd(db *sql.DB, event Event) error {
 }
 
 // applyDepRemove removes a dependency from a dep_rm event.
-func applyDepRemove(db *sql.DB, event Event) error {
+func applyDepRemove(db sqlExecutor, event Event) error {
 	dependsOn := event.Payload["depends_on"]
-	if dependsOn == "" {
-		return fmt.Errorf("dep_rm event missing depends_on")
-	}
 	depType := NormalizeDepType(event.Payload["dep_type"])
 	// Validate both issues exist before attempting removal.
 	if err := ensureIssueExists(db, event.IssueID); err != nil {
@@ -373,21 +646,301 @@ func applyDepRemove(db *sql.DB, event Event) error {
 	if err := ensureIssueExists(db, dependsOn); err != nil {
 		return err
 	}
-	// Delete the dependency edge if present.
-	_, err := db.Exec(
-		"DELETE FROM deps WHERE issue_id = ? AND depends_on_id = ? AND dep_type = ?",
+	// Delete the dependency edge if present, live or detached.
+	for _, table := range []string{"deps", "detached_deps"} {
+		_, err := db.Exec(
+			"DELETE FROM "+table+" WHERE issue_id = ? AND depends_on_id = ? AND dep_type = ?",
+			event.IssueID,
+			dependsOn,
+			depType,
+		)
+		if err != nil {
+			return fmt.Errorf("delete dependency: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyAssign sets or clears an issue assignee from an assign event.
+func applyAssign(db sqlExecutor, event Event) error {
+	assignee := event.Payload["assignee"]
+	result, err := db.Exec(
+		"UPDATE issues SET assignee = ?, updated_at = ? WHERE id = ?",
+		strings.TrimSpace(assignee),
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("update assignee: %w", err)
+	}
+	return requireRow(result, "assign for missing issue")
+}
+
+// applyDue sets or clears an issue's due date from a due event.
+func applyDue(db sqlExecutor, event Event) error {
+	dueAt := event.Payload["due_at"]
+	result, err := db.Exec(
+		"UPDATE issues SET due_at = ?, updated_at = ? WHERE id = ?",
+		strings.TrimSpace(dueAt),
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("update due date: %w", err)
+	}
+	return requireRow(result, "due date for missing issue")
+}
+
+// applyDefer sets the wake-up date from a defer event or clears it for an
+// undefer event.
+func applyDefer(db sqlExecutor, event Event) error {
+	until := ""
+	if event.Type == EventTypeDefer {
+		until = strings.TrimSpace(event.Payload["until"])
+	}
+	result, err := db.Exec(
+		"UPDATE issues SET deferred_until = ?, updated_at = ? WHERE id = ?",
+		until,
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("update deferral: %w", err)
+	}
+	return requireRow(result, "defer for missing issue")
+}
+
+// applyEstimate sets or clears an issue's estimate from an estimate event.
+func applyEstimate(db sqlExecutor, event Event) error {
+	estimate := event.Payload["estimate"]
+	result, err := db.Exec(
+		"UPDATE issues SET estimate = ?, updated_at = ? WHERE id = ?",
+		strings.TrimSpace(estimate),
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("update estimate: %w", err)
+	}
+	return requireRow(result, "estimate for missing issue")
+}
+
+// applyTimerStart records when and by whom an issue's work timer started.
+func applyTimerStart(db sqlExecutor, event Event) error {
+	result, err := db.Exec(
+		"UPDATE issues SET timer_started_at = ?, timer_started_by = ?, updated_at = ? WHERE id = ?",
+		event.Timestamp,
+		event.Actor,
+		event.Timestamp,
 		event.IssueID,
-		dependsOn,
-		depType,
 	)
 	if err != nil {
-		return fmt.Errorf("delete dependency: %w", err)
+		return fmt.Errorf("start timer: %w", err)
+	}
+	return requireRow(result, "timer start for missing issue")
+}
+
+// applyWorkLog adds logged minutes to an issue. Entries written by pb stop
+// carry started_at and also clear the running timer.
+func applyWorkLog(db sqlExecutor, event Event) error {
+	// validateEventPayload has already checked the minutes are positive.
+	minutes, _ := strconv.Atoi(strings.TrimSpace(event.Payload["minutes"]))
+	query := "UPDATE issues SET logged_minutes = logged_minutes + ?, updated_at = ? WHERE id = ?"
+	if event.Payload["started_at"] != "" {
+		query = "UPDATE issues SET logged_minutes = logged_minutes + ?, updated_at = ?, timer_started_at = '', timer_started_by = '' WHERE id = ?"
+	}
+	result, err := db.Exec(query, minutes, event.Timestamp, event.IssueID)
+	if err != nil {
+		return fmt.Errorf("log work: %w", err)
+	}
+	return requireRow(result, "work log for missing issue")
+}
+
+// applyClaim records the event actor as the claimant until the lease expires.
+func applyClaim(db sqlExecutor, event Event) error {
+	expiresAt := event.Payload["expires_at"]
+	result, err := db.Exec(
+		"UPDATE issues SET claimed_by = ?, claim_expires_at = ?, updated_at = ? WHERE id = ?",
+		event.Actor,
+		expiresAt,
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("claim issue: %w", err)
+	}
+	return requireRow(result, "claim for missing issue")
+}
+
+// applyRelease clears the claim on an issue.
+func applyRelease(db sqlExecutor, event Event) error {
+	result, err := db.Exec(
+		"UPDATE issues SET claimed_by = '', claim_expires_at = '', updated_at = ? WHERE id = ?",
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("release issue: %w", err)
+	}
+	return requireRow(result, "release for missing issue")
+}
+
+// applyLabelAdd attaches a label to an issue from a label_add event.
+func applyLabelAdd(db sqlExecutor, event Event) error {
+	label := event.Payload["label"]
+	if err := ensureIssueExists(db, event.IssueID); err != nil {
+		return err
+	}
+	// Insert the label, ignoring duplicates, and touch updated_at.
+	if _, err := db.Exec(
+		"INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)",
+		event.IssueID,
+		label,
+	); err != nil {
+		return fmt.Errorf("insert label: %w", err)
+	}
+	return touchIssue(db, event.IssueID, event.Timestamp)
+}
+
+// applyLabelRemove detaches a label from an issue from a label_rm event.
+func applyLabelRemove(db sqlExecutor, event Event) error {
+	label := event.Payload["label"]
+	if err := ensureIssueExists(db, event.IssueID); err != nil {
+		return err
+	}
+	// Delete the label if present and touch updated_at.
+	if _, err := db.Exec(
+		"DELETE FROM labels WHERE issue_id = ? AND label = ?",
+		event.IssueID,
+		label,
+	); err != nil {
+		return fmt.Errorf("delete label: %w", err)
+	}
+	return touchIssue(db, event.IssueID, event.Timestamp)
+}
+
+// applyFieldSet stores or clears a custom field from a field_set event.
+func applyFieldSet(db sqlExecutor, event Event) error {
+	name := event.Payload["field"]
+	if err := ensureIssueExists(db, event.IssueID); err != nil {
+		return err
+	}
+	// An empty value clears the field; otherwise replace the stored value.
+	if event.Payload["value"] == "" {
+		if _, err := db.Exec("DELETE FROM fields WHERE issue_id = ? AND name = ?", event.IssueID, name); err != nil {
+			return fmt.Errorf("clear field: %w", err)
+		}
+		return touchIssue(db, event.IssueID, event.Timestamp)
+	}
+	if _, err := db.Exec(
+		"INSERT OR REPLACE INTO fields (issue_id, name, value) VALUES (?, ?, ?)",
+		event.IssueID,
+		name,
+		event.Payload["value"],
+	); err != nil {
+		return fmt.Errorf("set field: %w", err)
+	}
+	return touchIssue(db, event.IssueID, event.Timestamp)
+}
+
+// applyDelete hides an issue and detaches every dependency edge touching it.
+// Comments are left alone so the history stays auditable.
+func applyDelete(db sqlExecutor, event Event) error {
+	result, err := db.Exec(
+		"UPDATE issues SET deleted_at = ?, delete_reason = ?, updated_at = ? WHERE id = ?",
+		event.Timestamp,
+		event.Payload["reason"],
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete issue: %w", err)
+	}
+	if err := requireRow(result, "delete for missing issue"); err != nil {
+		return err
+	}
+	// Move the edges aside, remembering which delete detached them.
+	if _, err := db.Exec(
+		`INSERT OR IGNORE INTO detached_deps (issue_id, depends_on_id, dep_type, deleted_id)
+		SELECT issue_id, depends_on_id, dep_type, ? FROM deps WHERE issue_id = ? OR depends_on_id = ?`,
+		event.IssueID,
+		event.IssueID,
+		event.IssueID,
+	); err != nil {
+		return fmt.Errorf("detach dependencies: %w", err)
+	}
+	if _, err := db.Exec("DELETE FROM deps WHERE issue_id = ? OR depends_on_id = ?", event.IssueID, event.IssueID); err != nil {
+		return fmt.Errorf("detach dependencies: %w", err)
 	}
 	return nil
 }
 
+// applyUndelete restores a deleted issue and reattaches its detached edges.
+// Edges whose other end is still deleted stay detached under that issue.
+func applyUndelete(db sqlExecutor, event Event) error {
+	result, err := db.Exec(
+		"UPDATE issues SET deleted_at = '', delete_reason = '', updated_at = ? WHERE id = ?",
+		event.Timestamp,
+		event.IssueID,
+	)
+	if err != nil {
+		return fmt.Errorf("undelete issue: %w", err)
+	}
+	if err := requireRow(result, "undelete for missing issue"); err != nil {
+		return err
+	}
+	const restorable = `NOT EXISTS (
+		SELECT 1 FROM issues i
+		WHERE i.id IN (detached_deps.issue_id, detached_deps.depends_on_id) AND i.deleted_at != ''
+	)`
+	if _, err := db.Exec(
+		`INSERT OR IGNORE INTO deps (issue_id, depends_on_id, dep_type)
+		SELECT issue_id, depends_on_id, dep_type FROM detached_deps WHERE deleted_id = ? AND `+restorable,
+		event.IssueID,
+	); err != nil {
+		return fmt.Errorf("reattach dependencies: %w", err)
+	}
+	if _, err := db.Exec("DELETE FROM detached_deps WHERE deleted_id = ? AND "+restorable, event.IssueID); err != nil {
+		return fmt.Errorf("reattach dependencies: %w", err)
+	}
+	// Hand the remaining edges to the deleted issue on their other end.
+	if _, err := db.Exec(
+		`UPDATE detached_deps
+		SET deleted_id = CASE WHEN issue_id = ? THEN depends_on_id ELSE issue_id END
+		WHERE deleted_id = ?`,
+		event.IssueID,
+		event.IssueID,
+	); err != nil {
+		return fmt.Errorf("reassign detached dependencies: %w", err)
+	}
+	return nil
+}
+
+// deletedEndpoint returns the first of the given issues that is deleted, if any.
+func deletedEndpoint(db sqlExecutor, ids ...string) (string, error) {
+	for _, id := range ids {
+		var deletedAt string
+		if err := db.QueryRow("SELECT deleted_at FROM issues WHERE id = ?", id).Scan(&deletedAt); err != nil {
+			return "", fmt.Errorf("check deleted issue: %w", err)
+		}
+		if deletedAt != "" {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+// touchIssue stamps updated_at for changes stored outside the issue row.
+func touchIssue(db sqlExecutor, issueID, timestamp string) error {
+	result, err := db.Exec("UPDATE issues SET updated_at = ? WHERE id = ?", timestamp, issueID)
+	if err != nil {
+		return fmt.Errorf("touch issue: %w", err)
+	}
+	return requireRow(result, "update for missing issue")
+}
+
 // ensureIssueExists verifies a referenced issue exists.
-func ensureIssueExists(db *sql.DB, issueID string) error {
+func ensureIssueExists(db sqlExecutor, issueID string) error {
 	exists, err := issueExists(db, issueID)
 	if err != nil {
 		return err
@@ -399,7 +952,7 @@ func ensureIssueExists(db *sql.DB, issueID string) error {
 }
 
 // updateIssueID swaps issue IDs and stamps updated_at.
-func updateIssueID(db *sql.DB, oldID, newID, timestamp string) error {
+func updateIssueID(db sqlExecutor, oldID, newID, timestamp string) error {
 	result, err := db.Exec(
 		"UPDATE issues SET id = ?, updated_at = ? WHERE id = ?",
 		newID,
@@ -413,18 +966,40 @@ func updateIssueID(db *sql.DB, oldID, newID, timestamp string) error {
 }
 
 // updateDepsForRename rewrites dependency edges for a renamed issue.
-func updateDepsForRename(db *sql.DB, oldID, newID string) error {
+func updateDepsForRename(db sqlExecutor, oldID, newID string) error {
 	if _, err := db.Exec("UPDATE deps SET issue_id = ? WHERE issue_id = ?", newID, oldID); err != nil {
 		return fmt.Errorf("rename dependency issue_id: %w", err)
 	}
 	if _, err := db.Exec("UPDATE deps SET depends_on_id = ? WHERE depends_on_id = ?", newID, oldID); err != nil {
 		return fmt.Errorf("rename dependency depends_on_id: %w", err)
 	}
+	// Detached edges keep their endpoints and owner in step with renames too.
+	for _, column := range []string{"issue_id", "depends_on_id", "deleted_id"} {
+		query := fmt.Sprintf("UPDATE detached_deps SET %s = ? WHERE %s = ?", column, column)
+		if _, err := db.Exec(query, newID, oldID); err != nil {
+			return fmt.Errorf("rename detached dependency %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// moveIssueRowsForRename re-keys the rows other tables hold for an issue
+// (labels, custom fields, comments) from its old ID to its new one.
+func moveIssueRowsForRename(db sqlExecutor, oldID, newID string) error {
+	if _, err := db.Exec("UPDATE labels SET issue_id = ? WHERE issue_id = ?", newID, oldID); err != nil {
+		return fmt.Errorf("rename labels: %w", err)
+	}
+	if _, err := db.Exec("UPDATE fields SET issue_id = ? WHERE issue_id = ?", newID, oldID); err != nil {
+		return fmt.Errorf("rename fields: %w", err)
+	}
+	if _, err := db.Exec("UPDATE comments SET issue_id = ? WHERE issue_id = ?", newID, oldID); err != nil {
+		return fmt.Errorf("rename comments: %w", err)
+	}
 	return nil
 }
 
 // upsertRename records an issue ID rename mapping.
-func upsertRename(db *sql.DB, oldID, newID string) error {
+func upsertRename(db sqlExecutor, oldID, newID string) error {
 	if _, err := db.Exec(
 		"INSERT OR REPLACE INTO renames (old_id, new_id) VALUES (?, ?)",
 		oldID,
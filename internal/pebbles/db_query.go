@@ -2,11 +2,14 @@ package pebbles
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 )
 
-// ListIssues returns all issues ordered by ID.
+// ListIssues returns all issues ordered by ID, including deleted ones.
 func ListIssues(root string) ([]Issue, error) {
 	if err := EnsureCache(root); err != nil {
 		return nil, err
@@ -16,7 +19,7 @@ func ListIssues(root string) ([]Issue, error) {
 		return nil, err
 	}
 	defer func() { _ = db.Close() }()
-	return listIssues(db)
+	return listIssues(db, true)
 }
 
 // ListIssueHierarchy returns issues ordered with parent-child indentation.
@@ -29,8 +32,8 @@ func ListIssueHierarchy(root string) ([]IssueHierarchyItem, error) {
 		return nil, err
 	}
 	defer func() { _ = db.Close() }()
-	// Load issues and parent-child edges, then build a stable hierarchy.
-	issues, err := listIssues(db)
+	// Load live issues and parent-child edges, then build a stable hierarchy.
+	issues, err := listIssues(db, false)
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +44,14 @@ func ListIssueHierarchy(root string) ([]IssueHierarchyItem, error) {
 	return buildIssueHierarchy(issues, childrenByParent, childSet), nil
 }
 
-// listIssues returns all issues ordered by ID.
-func listIssues(db *sql.DB) ([]Issue, error) {
-	// Query all issues in a stable order for output.
-	rows, err := db.Query(
-		"SELECT id, title, description, issue_type, status, priority, created_at, updated_at, closed_at FROM issues ORDER BY id",
-	)
+// listIssues returns issues ordered by ID, optionally including deleted ones.
+func listIssues(db *sql.DB, includeDeleted bool) ([]Issue, error) {
+	// Query issues in a stable order for output.
+	query := "SELECT " + issueColumns("issues") + " FROM issues"
+	if !includeDeleted {
+		query += " WHERE deleted_at = ''"
+	}
+	rows, err := db.Query(query + " ORDER BY id")
 	if err != nil {
 		return nil, fmt.Errorf("list issues: %w", err)
 	}
@@ -140,8 +145,22 @@ func buildIssueHierarchy(issues []Issue, childrenByParent map[string][]string, c
 	return items
 }
 
-// GetIssue returns a single issue and its dependencies.
+// GetIssue returns a single issue and its dependencies. Deleted issues are
+// reported as an error; use GetIssueIncludingDeleted to read them.
 func GetIssue(root, id string) (Issue, []string, error) {
+	issue, deps, err := GetIssueIncludingDeleted(root, id)
+	if err != nil {
+		return Issue{}, nil, err
+	}
+	if issue.DeletedAt != "" {
+		return Issue{}, nil, fmt.Errorf("issue %s is deleted (see pb show --deleted or pb undelete)", issue.ID)
+	}
+	return issue, deps, nil
+}
+
+// GetIssueIncludingDeleted returns a single issue and its dependencies even
+// when the issue is deleted.
+func GetIssueIncludingDeleted(root, id string) (Issue, []string, error) {
 	if err := EnsureCache(root); err != nil {
 		return Issue{}, nil, err
 	}
@@ -156,7 +175,7 @@ func GetIssue(root, id string) (Issue, []string, error) {
 	}
 	// Fetch the issue row by ID.
 	row := db.QueryRow(
-		"SELECT id, title, description, issue_type, status, priority, created_at, updated_at, closed_at FROM issues WHERE id = ?",
+		"SELECT "+issueColumns("issues")+" FROM issues WHERE id = ?",
 		resolvedID,
 	)
 	issue, err := scanIssue(row)
@@ -171,8 +190,14 @@ func GetIssue(root, id string) (Issue, []string, error) {
 	return issue, deps, nil
 }
 
-// ListReadyIssues returns issues that have no open blockers.
+// ListReadyIssues returns issues that have no open blockers, no active claim,
+// and no active deferral, in work order (see SortReadyIssues). Open means any status outside
+// the workflow's done category.
 func ListReadyIssues(root string) ([]Issue, error) {
+	workflow, err := LoadWorkflow(root)
+	if err != nil {
+		return nil, err
+	}
 	if err := EnsureCache(root); err != nil {
 		return nil, err
 	}
@@ -181,40 +206,51 @@ func ListReadyIssues(root string) ([]Issue, error) {
 		return nil, err
 	}
 	defer func() { _ = db.Close() }()
-	// Select issues that are not closed and have no deps on open issues.
+	// Select issues that are not done and have no deps on unfinished issues.
+	done := workflow.StatusesIn(StatusCategoryDone)
 	query := `
-		SELECT i.id, i.title, i.description, i.issue_type, i.status, i.priority, i.created_at, i.updated_at, i.closed_at
+		SELECT ` + issueColumns("i") + `
 		FROM issues i
-		WHERE i.status != ?
+		WHERE i.status NOT IN (` + sqlPlaceholders(len(done)) + `) AND i.deleted_at = ''
 		AND NOT EXISTS (
 			SELECT 1 FROM deps d
 			JOIN issues di ON di.id = d.depends_on_id
-			WHERE d.issue_id = i.id AND d.dep_type = ? AND di.status != ?
+			WHERE d.issue_id = i.id AND d.dep_type = ? AND di.status NOT IN (` + sqlPlaceholders(len(done)) + `)
 		)
 		ORDER BY i.id
 	`
-	rows, err := db.Query(query, StatusClosed, DepTypeBlocks, StatusClosed)
+	args := append(stringArgs(done), DepTypeBlocks)
+	rows, err := db.Query(query, append(args, stringArgs(done)...)...)
 	if err != nil {
 		return nil, fmt.Errorf("ready issues: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 	var issues []Issue
-	// Scan candidate issues into memory.
+	now := time.Now()
+	// Scan candidate issues, leaving out claimed and deferred ones.
 	for rows.Next() {
 		issue, err := scanIssue(rows)
 		if err != nil {
 			return nil, err
 		}
+		if issue.ClaimActive(now) || issue.DeferActive(now) {
+			continue
+		}
 		issues = append(issues, issue)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("ready issues rows: %w", err)
 	}
+	SortReadyIssues(issues, now)
 	return issues, nil
 }
 
-// ListBlockedIssues returns issues that depend on open blockers.
+// ListBlockedIssues returns issues that depend on blockers that are not done.
 func ListBlockedIssues(root string) ([]BlockedIssue, error) {
+	workflow, err := LoadWorkflow(root)
+	if err != nil {
+		return nil, err
+	}
 	if err := EnsureCache(root); err != nil {
 		return nil, err
 	}
@@ -223,18 +259,20 @@ func ListBlockedIssues(root string) ([]BlockedIssue, error) {
 		return nil, err
 	}
 	defer func() { _ = db.Close() }()
-	// Join issues against their open blocking dependencies.
+	// Join issues against their unfinished blocking dependencies.
+	done := workflow.StatusesIn(StatusCategoryDone)
 	query := `
-		SELECT
-			i.id, i.title, i.description, i.issue_type, i.status, i.priority, i.created_at, i.updated_at, i.closed_at,
-			bi.id, bi.title, bi.description, bi.issue_type, bi.status, bi.priority, bi.created_at, bi.updated_at, bi.closed_at
+		SELECT ` + issueColumns("i") + `, ` + issueColumns("bi") + `
 		FROM issues i
 		JOIN deps d ON d.issue_id = i.id AND d.dep_type = ?
 		JOIN issues bi ON bi.id = d.depends_on_id
-		WHERE i.status != ? AND bi.status != ?
+		WHERE i.status NOT IN (` + sqlPlaceholders(len(done)) + `)
+		AND bi.status NOT IN (` + sqlPlaceholders(len(done)) + `)
+		AND i.deleted_at = ''
 		ORDER BY i.id, bi.id
 	`
-	rows, err := db.Query(query, DepTypeBlocks, StatusClosed, StatusClosed)
+	args := append([]any{DepTypeBlocks}, stringArgs(done)...)
+	rows, err := db.Query(query, append(args, stringArgs(done)...)...)
 	if err != nil {
 		return nil, fmt.Errorf("blocked issues: %w", err)
 	}
@@ -276,11 +314,72 @@ func IssueExists(root, id string) (bool, error) {
 	return issueExists(db, resolvedID)
 }
 
-// scanIssue scans a single issue row from a row scanner.
-func scanIssue(scanner interface{ Scan(...any) error }) (Issue, error) {
-	var issue Issue
-	// Map columns into the Issue struct in order.
-	if err := scanner.Scan(
+// ListSkippedEvents returns events the cache replay skipped, in replay order.
+func ListSkippedEvents(root string) ([]SkippedEvent, error) {
+	if err := EnsureCache(root); err != nil {
+		return nil, err
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	rows, err := db.Query("SELECT event_type, issue_id, timestamp, version, reason FROM skipped_events ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("query skipped events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var skipped []SkippedEvent
+	for rows.Next() {
+		var event SkippedEvent
+		if err := rows.Scan(&event.Type, &event.IssueID, &event.Timestamp, &event.Version, &event.Reason); err != nil {
+			return nil, fmt.Errorf("scan skipped event: %w", err)
+		}
+		skipped = append(skipped, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("skipped event rows: %w", err)
+	}
+	return skipped, nil
+}
+
+// sqlPlaceholders returns a comma-separated list of n query placeholders.
+func sqlPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// stringArgs converts strings into query arguments.
+func stringArgs(values []string) []any {
+	args := make([]any, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+	return args
+}
+
+// issueColumns returns the issue select list for a table alias, matching the
+// order scanIssue expects. Labels are folded into a single comma-joined column
+// and custom fields into a JSON object column.
+func issueColumns(alias string) string {
+	columns := []string{"id", "title", "description", "issue_type", "status", "priority", "created_at", "updated_at", "closed_at", "resolution", "close_reason", "assignee", "claimed_by", "claim_expires_at", "deleted_at", "delete_reason", "due_at", "deferred_until", "estimate", "logged_minutes", "timer_started_at", "timer_started_by"}
+	qualified := make([]string, 0, len(columns)+2)
+	for _, column := range columns {
+		qualified = append(qualified, alias+"."+column)
+	}
+	qualified = append(qualified, fmt.Sprintf(
+		"(SELECT COALESCE(group_concat(label, ','), '') FROM labels WHERE labels.issue_id = %s.id)",
+		alias,
+	))
+	qualified = append(qualified, fmt.Sprintf(
+		"(SELECT COALESCE(json_group_object(name, value), '{}') FROM fields WHERE fields.issue_id = %s.id)",
+		alias,
+	))
+	return strings.Join(qualified, ", ")
+}
+
+// issueScanTargets returns scan destinations for the columns in issueColumns.
+func issueScanTargets(issue *Issue, labels, fields *string) []any {
+	return []any{
 		&issue.ID,
 		&issue.Title,
 		&issue.Description,
@@ -290,9 +389,60 @@ func scanIssue(scanner interface{ Scan(...any) error }) (Issue, error) {
 		&issue.CreatedAt,
 		&issue.UpdatedAt,
 		&issue.ClosedAt,
-	); err != nil {
+		&issue.Resolution,
+		&issue.CloseReason,
+		&issue.Assignee,
+		&issue.ClaimedBy,
+		&issue.ClaimExpiresAt,
+		&issue.DeletedAt,
+		&issue.DeleteReason,
+		&issue.DueAt,
+		&issue.DeferredUntil,
+		&issue.Estimate,
+		&issue.LoggedMinutes,
+		&issue.TimerStartedAt,
+		&issue.TimerStartedBy,
+		labels,
+		fields,
+	}
+}
+
+// splitLabels converts the joined label column into a sorted slice.
+func splitLabels(joined string) []string {
+	if joined == "" {
+		return nil
+	}
+	labels := strings.Split(joined, ",")
+	sort.Strings(labels)
+	return labels
+}
+
+// decodeFields converts the JSON fields column into a map, nil when empty.
+func decodeFields(joined string) (map[string]string, error) {
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(joined), &fields); err != nil {
+		return nil, fmt.Errorf("decode fields: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// scanIssue scans a single issue row from a row scanner.
+func scanIssue(scanner interface{ Scan(...any) error }) (Issue, error) {
+	var issue Issue
+	var labels, fields string
+	// Map columns into the Issue struct in order.
+	if err := scanner.Scan(issueScanTargets(&issue, &labels, &fields)...); err != nil {
 		return Issue{}, fmt.Errorf("scan issue: %w", err)
 	}
+	issue.Labels = splitLabels(labels)
+	decoded, err := decodeFields(fields)
+	if err != nil {
+		return Issue{}, err
+	}
+	issue.Fields = decoded
 	return issue, nil
 }
 
@@ -300,28 +450,20 @@ func scanIssue(scanner interface{ Scan(...any) error }) (Issue, error) {
 func scanIssuePair(scanner interface{ Scan(...any) error }) (Issue, Issue, error) {
 	var issue Issue
 	var blocker Issue
-	if err := scanner.Scan(
-		&issue.ID,
-		&issue.Title,
-		&issue.Description,
-		&issue.IssueType,
-		&issue.Status,
-		&issue.Priority,
-		&issue.CreatedAt,
-		&issue.UpdatedAt,
-		&issue.ClosedAt,
-		&blocker.ID,
-		&blocker.Title,
-		&blocker.Description,
-		&blocker.IssueType,
-		&blocker.Status,
-		&blocker.Priority,
-		&blocker.CreatedAt,
-		&blocker.UpdatedAt,
-		&blocker.ClosedAt,
-	); err != nil {
+	var issueLabels, blockerLabels, issueFields, blockerFields string
+	targets := append(issueScanTargets(&issue, &issueLabels, &issueFields), issueScanTargets(&blocker, &blockerLabels, &blockerFields)...)
+	if err := scanner.Scan(targets...); err != nil {
 		return Issue{}, Issue{}, fmt.Errorf("scan issue pair: %w", err)
 	}
+	issue.Labels = splitLabels(issueLabels)
+	blocker.Labels = splitLabels(blockerLabels)
+	var err error
+	if issue.Fields, err = decodeFields(issueFields); err != nil {
+		return Issue{}, Issue{}, err
+	}
+	if blocker.Fields, err = decodeFields(blockerFields); err != nil {
+		return Issue{}, Issue{}, err
+	}
 	return issue, blocker, nil
 }
 
@@ -329,7 +471,7 @@ func scanIssuePair(scanner interface{ Scan(...any) error }) (Issue, Issue, error
 func getIssueByID(db *sql.DB, id string) (Issue, error) {
 	// Query by ID for dependency tree and status helpers.
 	row := db.QueryRow(
-		"SELECT id, title, description, issue_type, status, priority, created_at, updated_at, closed_at FROM issues WHERE id = ?",
+		"SELECT "+issueColumns("issues")+" FROM issues WHERE id = ?",
 		id,
 	)
 	issue, err := scanIssue(row)
@@ -0,0 +1,50 @@
+package pebbles
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDeferHidesIssueFromReady verifies deferred issues leave ready work until
+// their date and return after undefer or once the date arrives.
+func TestDeferHidesIssueFromReady(t *testing.T) {
+	root := setupClaimProject(t)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(dateLayout)
+	if err := AppendEvent(root, NewDeferEvent("pb-high", tomorrow, "2024-01-02T00:00:00Z")); err != nil {
+		t.Fatalf("append defer: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != "pb-low" {
+		t.Fatalf("expected only pb-low ready while pb-high is deferred, got %v", ready)
+	}
+	if _, err := ClaimIssue(root, "pb-high", "a@example.com", time.Hour); err == nil {
+		t.Fatalf("expected claiming a deferred issue to fail")
+	}
+	issue, _, err := GetIssue(root, "pb-high")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.DeferredUntil != tomorrow || issue.DeferActive(time.Now().AddDate(0, 0, 1)) {
+		t.Fatalf("expected deferral to end on %s, got %q", tomorrow, issue.DeferredUntil)
+	}
+
+	if err := AppendEvent(root, NewUndeferEvent("pb-high", "2024-01-02T00:01:00Z")); err != nil {
+		t.Fatalf("append undefer: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	ready, err = ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 2 {
+		t.Fatalf("expected undefer to make pb-high ready again, got %d issues", len(ready))
+	}
+}
@@ -0,0 +1,272 @@
+package pebbles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ProblemConflictMarker flags a git merge conflict marker line.
+	ProblemConflictMarker = "conflict_marker"
+	// ProblemMalformedJSON flags a log line that is not a valid event object.
+	ProblemMalformedJSON = "malformed_json"
+	// ProblemUnknownEventType flags an event type the cache does not understand.
+	ProblemUnknownEventType = "unknown_event_type"
+	// ProblemBadTimestamp flags a timestamp that is not RFC3339.
+	ProblemBadTimestamp = "bad_timestamp"
+	// ProblemEmptyTitle flags a create or title update without a title.
+	ProblemEmptyTitle = "empty_title"
+	// ProblemDuplicateCreate flags a create for an issue that already exists.
+	ProblemDuplicateCreate = "duplicate_create"
+	// ProblemMissingIssue flags an event that targets an unknown issue.
+	ProblemMissingIssue = "missing_issue"
+	// ProblemDanglingDep flags a dependency whose target issue is unknown.
+	ProblemDanglingDep = "dangling_dep"
+	// ProblemRenameCycle flags renames that loop back to an earlier ID.
+	ProblemRenameCycle = "rename_cycle"
+	// ProblemRenameConflict flags a rename onto an ID that is already in use.
+	ProblemRenameConflict = "rename_conflict"
+	// ProblemInvalidPayload flags an event missing fields it requires.
+	ProblemInvalidPayload = "invalid_payload"
+	// ProblemMissingComment flags a comment edit or delete for an unknown comment.
+	ProblemMissingComment = "missing_comment"
+)
+
+// LogProblem describes a single integrity problem found in the event log.
+type LogProblem struct {
+	Line      int
+	Code      string
+	EventType string
+	IssueID   string
+	Message   string
+}
+
+// LogCheckReport summarizes an event log integrity check.
+type LogCheckReport struct {
+	Lines    int
+	Events   int
+	Problems []LogProblem
+}
+
+// doctorState is an in-memory model of the cache used to validate replay.
+type doctorState struct {
+	issues   map[string]bool
+	renames  map[string]string
+	comments map[string]bool
+}
+
+// CheckEventLog scans the event log and reports every problem with its line.
+// Unlike a cache rebuild it keeps going after a bad event, replaying the rest
+// of the log in cache order so later problems are still found.
+func CheckEventLog(root string) (LogCheckReport, error) {
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		return LogCheckReport{}, fmt.Errorf("read events log: %w", err)
+	}
+	return checkEventLogData(data), nil
+}
+
+// checkEventLogData runs the syntactic and semantic checks over raw log data.
+func checkEventLogData(data []byte) LogCheckReport {
+	lines := splitLogLines(data)
+	report := LogCheckReport{Lines: len(lines)}
+	// Decode every line first so syntax problems are reported independently.
+	var entries []EventLogEntry
+	for _, line := range lines {
+		if isConflictMarker(line.Text) {
+			report.Problems = append(report.Problems, LogProblem{
+				Line:    line.Number,
+				Code:    ProblemConflictMarker,
+				Message: "git merge conflict marker; run pb resolve",
+			})
+			continue
+		}
+		event, err := decodeEvent(line.Text)
+		if err != nil {
+			report.Problems = append(report.Problems, LogProblem{
+				Line:    line.Number,
+				Code:    ProblemMalformedJSON,
+				Message: fmt.Sprintf("invalid JSON: %v", err),
+			})
+			continue
+		}
+		entries = append(entries, EventLogEntry{Line: line.Number, Event: event, Raw: string(line.Text)})
+	}
+	report.Events = len(entries)
+	for _, entry := range entries {
+		report.Problems = append(report.Problems, checkEventFields(entry)...)
+	}
+	// Replay in cache order so references are checked against the state the
+	// cache would see when applying each event.
+	events := make([]Event, len(entries))
+	for i, entry := range entries {
+		events[i] = entry.Event
+	}
+	state := &doctorState{issues: make(map[string]bool), renames: make(map[string]string), comments: make(map[string]bool)}
+	for _, index := range replayOrder(events, 0) {
+		entry := entries[index]
+		if problem, ok := state.apply(entry); !ok {
+			report.Problems = append(report.Problems, problem)
+		}
+	}
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		return report.Problems[i].Line < report.Problems[j].Line
+	})
+	return report
+}
+
+// checkEventFields validates the parts of an event that need no replay state.
+func checkEventFields(entry EventLogEntry) []LogProblem {
+	event := entry.Event
+	var problems []LogProblem
+	if _, err := time.Parse(time.RFC3339Nano, event.Timestamp); err != nil {
+		problems = append(problems, newLogProblem(entry, ProblemBadTimestamp,
+			fmt.Sprintf("timestamp %q is not RFC3339", event.Timestamp)))
+	}
+	if !IsKnownEventType(event.Type) {
+		problems = append(problems, newLogProblem(entry, ProblemUnknownEventType,
+			fmt.Sprintf("unknown event type %q; replay skips it", event.Type)))
+	} else if err := validateEventPayload(event); err != nil {
+		code := ProblemInvalidPayload
+		var payloadErr *payloadError
+		if errors.As(err, &payloadErr) && payloadErr.key == "title" {
+			code = ProblemEmptyTitle
+		}
+		problems = append(problems, newLogProblem(entry, code, err.Error()))
+	}
+	if strings.TrimSpace(event.IssueID) == "" {
+		problems = append(problems, newLogProblem(entry, ProblemInvalidPayload, "event is missing issue_id"))
+	}
+	return problems
+}
+
+// apply validates and applies one event, returning a problem when it would fail.
+func (state *doctorState) apply(entry EventLogEntry) (LogProblem, bool) {
+	event := entry.Event
+	if strings.TrimSpace(event.IssueID) == "" {
+		return LogProblem{}, true
+	}
+	// Payload problems are reported by checkEventFields; like a failed replay,
+	// such an event changes nothing.
+	if !IsKnownEventType(event.Type) || validateEventPayload(event) != nil {
+		return LogProblem{}, true
+	}
+	switch event.Type {
+	case EventTypeCreate:
+		if state.issues[event.IssueID] {
+			return newLogProblem(entry, ProblemDuplicateCreate,
+				fmt.Sprintf("issue %s is already created; this create is ignored", event.IssueID)), false
+		}
+		state.issues[event.IssueID] = true
+	case EventTypeRename:
+		return state.applyRename(entry)
+	case EventTypeCommentEdit, EventTypeCommentDelete:
+		if _, problem, ok := state.requireIssue(entry, event.IssueID); !ok {
+			return problem, false
+		}
+		commentID := strings.TrimSpace(event.Payload["comment_id"])
+		if !state.comments[commentID] {
+			return newLogProblem(entry, ProblemMissingComment,
+				fmt.Sprintf("%s event targets missing or deleted comment %s", event.Type, commentID)), false
+		}
+		if event.Type == EventTypeCommentDelete {
+			state.comments[commentID] = false
+		}
+	case EventTypeDepAdd, EventTypeDepRemove:
+		if _, problem, ok := state.requireIssue(entry, event.IssueID); !ok {
+			return problem, false
+		}
+		dependsOn := strings.TrimSpace(event.Payload["depends_on"])
+		target, err := state.resolve(dependsOn)
+		if err != nil {
+			return newLogProblem(entry, ProblemRenameCycle, err.Error()), false
+		}
+		if !state.issues[target] {
+			return newLogProblem(entry, ProblemDanglingDep,
+				fmt.Sprintf("dependency target %s does not exist", dependsOn)), false
+		}
+	default:
+		if _, problem, ok := state.requireIssue(entry, event.IssueID); !ok {
+			return problem, false
+		}
+		if event.Type == EventTypeComment {
+			state.comments[CommentID(event)] = true
+		}
+	}
+	return LogProblem{}, true
+}
+
+// applyRename validates a rename against the current IDs and records it.
+func (state *doctorState) applyRename(entry EventLogEntry) (LogProblem, bool) {
+	event := entry.Event
+	newID := strings.TrimSpace(event.Payload["new_id"])
+	oldID, problem, ok := state.requireIssue(entry, event.IssueID)
+	if !ok {
+		return problem, false
+	}
+	resolvedNewID, err := state.resolve(newID)
+	if err != nil {
+		return newLogProblem(entry, ProblemRenameCycle, err.Error()), false
+	}
+	if oldID == newID || resolvedNewID == oldID {
+		return newLogProblem(entry, ProblemRenameCycle,
+			fmt.Sprintf("rename of %s to %s leads back to itself", event.IssueID, newID)), false
+	}
+	if resolvedNewID != newID {
+		return newLogProblem(entry, ProblemRenameConflict,
+			fmt.Sprintf("rename target %s is already renamed to %s", newID, resolvedNewID)), false
+	}
+	if state.issues[newID] {
+		return newLogProblem(entry, ProblemRenameConflict,
+			fmt.Sprintf("rename target %s already exists", newID)), false
+	}
+	delete(state.issues, oldID)
+	state.issues[newID] = true
+	state.renames[oldID] = newID
+	return LogProblem{}, true
+}
+
+// requireIssue resolves an event's issue ID and checks the issue exists.
+func (state *doctorState) requireIssue(entry EventLogEntry, id string) (string, LogProblem, bool) {
+	resolved, err := state.resolve(id)
+	if err != nil {
+		return "", newLogProblem(entry, ProblemRenameCycle, err.Error()), false
+	}
+	if !state.issues[resolved] {
+		return "", newLogProblem(entry, ProblemMissingIssue,
+			fmt.Sprintf("%s event targets missing issue %s", entry.Event.Type, id)), false
+	}
+	return resolved, LogProblem{}, true
+}
+
+// resolve follows rename mappings the same way the cache does.
+func (state *doctorState) resolve(id string) (string, error) {
+	current := strings.TrimSpace(id)
+	visited := make(map[string]bool)
+	for {
+		if visited[current] {
+			return "", fmt.Errorf("rename cycle detected for %s", id)
+		}
+		visited[current] = true
+		next, ok := state.renames[current]
+		if !ok {
+			return current, nil
+		}
+		current = next
+	}
+}
+
+// newLogProblem builds a problem for a log entry.
+func newLogProblem(entry EventLogEntry, code, message string) LogProblem {
+	return LogProblem{
+		Line:      entry.Line,
+		Code:      code,
+		EventType: entry.Event.Type,
+		IssueID:   entry.Event.IssueID,
+		Message:   message,
+	}
+}
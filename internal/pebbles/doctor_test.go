@@ -0,0 +1,122 @@
+package pebbles
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckEventLogReportsProblemsWithLines(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	lines := []string{
+		`{"type":"create","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"title":"First"}}`,
+		`{"type":"create","timestamp":"2024-01-01T00:00:01Z","issue_id":"pb-1","payload":{"title":"Again"}}`,
+		`{"type":"create","timestamp":"2024-01-01T00:00:02Z","issue_id":"pb-2","payload":{"title":" "}}`,
+		`not json`,
+		`{"type":"update","timestamp":"yesterday","issue_id":"pb-9","payload":{"priority":"1"}}`,
+		`{"type":"dep_add","timestamp":"2024-01-01T00:00:03Z","issue_id":"pb-1","payload":{"depends_on":"pb-404","dep_type":"blocks"}}`,
+		`{"type":"teleport","timestamp":"2024-01-01T00:00:04Z","issue_id":"pb-1","payload":{}}`,
+		`{"type":"rename","timestamp":"2024-01-01T00:00:05Z","issue_id":"pb-1","payload":{"new_id":"pb-1"}}`,
+	}
+	if err := os.WriteFile(EventsPath(root), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	report, err := CheckEventLog(root)
+	if err != nil {
+		t.Fatalf("check event log: %v", err)
+	}
+	want := []struct {
+		line int
+		code string
+	}{
+		{2, ProblemDuplicateCreate},
+		{3, ProblemEmptyTitle},
+		{4, ProblemMalformedJSON},
+		{5, ProblemBadTimestamp},
+		{5, ProblemMissingIssue},
+		{6, ProblemDanglingDep},
+		{7, ProblemUnknownEventType},
+		{8, ProblemRenameCycle},
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %+v", len(want), report.Problems)
+	}
+	for i, expected := range want {
+		problem := report.Problems[i]
+		if problem.Line != expected.line || problem.Code != expected.code {
+			t.Fatalf("problem %d: expected line %d %s, got line %d %s", i, expected.line, expected.code, problem.Line, problem.Code)
+		}
+	}
+	if report.Events != 7 {
+		t.Fatalf("expected 7 decoded events, got %d", report.Events)
+	}
+}
+
+func TestCheckEventLogFollowsRenames(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-1", "Parent", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewCreateEvent("pb-2", "Child", "", "task", "2024-01-01T00:00:01Z", 2),
+		NewRenameEvent("pb-2", "pb-1.1", "2024-01-01T00:00:02Z"),
+		NewDepAddEvent("pb-2", "pb-1", DepTypeParentChild, "2024-01-01T00:00:03Z"),
+		NewCloseEvent("pb-1.1", "2024-01-01T00:00:04Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	report, err := CheckEventLog(root)
+	if err != nil {
+		t.Fatalf("check event log: %v", err)
+	}
+	if len(report.Problems) != 0 {
+		t.Fatalf("expected a clean log, got %+v", report.Problems)
+	}
+}
+
+func TestCheckEventLogMatchesReplayPayloadChecks(t *testing.T) {
+	for eventType, spec := range eventSpecs {
+		if len(spec.keys) == 0 && spec.check == nil {
+			continue
+		}
+		t.Run(eventType, func(t *testing.T) {
+			root := t.TempDir()
+			if err := InitProject(root); err != nil {
+				t.Fatalf("init project: %v", err)
+			}
+			// An empty payload and no actor fail every required key and check.
+			events := []Event{
+				NewCreateEvent("pb-1", "First", "", "task", "2024-01-01T00:00:00Z", 2),
+				{Type: eventType, Timestamp: "2024-01-01T00:00:01Z", IssueID: "pb-1", Payload: map[string]string{}},
+			}
+			var lines []string
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					t.Fatalf("marshal event: %v", err)
+				}
+				lines = append(lines, string(data))
+			}
+			if err := os.WriteFile(EventsPath(root), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+				t.Fatalf("write events log: %v", err)
+			}
+			report, err := CheckEventLog(root)
+			if err != nil {
+				t.Fatalf("check event log: %v", err)
+			}
+			if len(report.Problems) != 1 || report.Problems[0].Line != 2 {
+				t.Fatalf("expected one problem on line 2, got %+v", report.Problems)
+			}
+			err = RebuildCache(root)
+			if err == nil || !strings.Contains(err.Error(), report.Problems[0].Message) {
+				t.Fatalf("expected replay to fail with %q, got %v", report.Problems[0].Message, err)
+			}
+		})
+	}
+}
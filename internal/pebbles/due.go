@@ -0,0 +1,133 @@
+package pebbles
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRelativeDate resolves a due or defer date to YYYY-MM-DD relative to
+// now's local date. It accepts dates, "today", "tomorrow", weekday names (the
+// next such day after today), and offsets like "+3d" or "+2w". "none" or an
+// empty input returns "" so callers can clear the date.
+func ParseRelativeDate(input string, now time.Time) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(input))
+	today := localDate(now)
+	switch value {
+	case "", "none":
+		return "", nil
+	case "today":
+		return today.Format(dateLayout), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1).Format(dateLayout), nil
+	}
+	// Weekday names pick the next occurrence, a week out on the same day.
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			offset := (int(day) - int(today.Weekday()) + 7) % 7
+			if offset == 0 {
+				offset = 7
+			}
+			return today.AddDate(0, 0, offset).Format(dateLayout), nil
+		}
+	}
+	if strings.HasPrefix(value, "+") && len(value) > 2 {
+		count, err := strconv.Atoi(value[1 : len(value)-1])
+		if err == nil && count >= 0 {
+			switch value[len(value)-1] {
+			case 'd':
+				return today.AddDate(0, 0, count).Format(dateLayout), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*count).Format(dateLayout), nil
+			}
+		}
+	}
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, tomorrow, a weekday, +Nd, +Nw, or none)", input)
+	}
+	return parsed.Format(dateLayout), nil
+}
+
+// DueInDays returns the whole days from now's local date until a due date;
+// negative values mean the issue is overdue. The bool is false when the
+// issue has no valid due date.
+func DueInDays(dueAt string, now time.Time) (int, bool) {
+	due, err := time.Parse(dateLayout, dueAt)
+	if err != nil {
+		return 0, false
+	}
+	today := localDate(now)
+	due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, today.Location())
+	// Round to absorb daylight-saving shifts between the two midnights.
+	return int(due.Sub(today).Round(24*time.Hour) / (24 * time.Hour)), true
+}
+
+// DeferActive reports whether an issue is deferred past now's local date.
+// A deferred issue wakes up on its deferred_until date.
+func (issue Issue) DeferActive(now time.Time) bool {
+	days, ok := DueInDays(issue.DeferredUntil, now)
+	return ok && days > 0
+}
+
+// IsOverdue reports whether an issue's due date is before today.
+func IsOverdue(issue Issue, now time.Time) bool {
+	days, ok := DueInDays(issue.DueAt, now)
+	return ok && days < 0
+}
+
+// FormatDueRelative describes a due date relative to today, e.g. "due in 2
+// days" or "overdue by 1 day".
+func FormatDueRelative(dueAt string, now time.Time) string {
+	days, ok := DueInDays(dueAt, now)
+	switch {
+	case !ok:
+		return ""
+	case days == 0:
+		return "due today"
+	case days == 1:
+		return "due tomorrow"
+	case days > 1:
+		return fmt.Sprintf("due in %d days", days)
+	case days == -1:
+		return "overdue by 1 day"
+	default:
+		return fmt.Sprintf("overdue by %d days", -days)
+	}
+}
+
+// SortReadyIssues orders a work queue: overdue issues first, then by
+// priority, then the earliest due date (issues without one last), then the
+// oldest issue, then ID for stability.
+func SortReadyIssues(issues []Issue, now time.Time) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		left, right := issues[i], issues[j]
+		if leftOverdue, rightOverdue := IsOverdue(left, now), IsOverdue(right, now); leftOverdue != rightOverdue {
+			return leftOverdue
+		}
+		if left.Priority != right.Priority {
+			return left.Priority < right.Priority
+		}
+		if left.DueAt != right.DueAt {
+			if left.DueAt == "" || right.DueAt == "" {
+				return right.DueAt == ""
+			}
+			if cmp := compareInstants(left.DueAt, right.DueAt); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		if cmp := compareInstants(left.CreatedAt, right.CreatedAt); cmp != 0 {
+			return cmp < 0
+		}
+		return left.ID < right.ID
+	})
+}
+
+// localDate returns midnight of now's date in its own location.
+func localDate(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
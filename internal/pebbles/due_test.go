@@ -0,0 +1,99 @@
+package pebbles
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDate(t *testing.T) {
+	// 2024-03-06 is a Wednesday.
+	now := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		"2024-04-01": "2024-04-01",
+		"today":      "2024-03-06",
+		"Tomorrow":   "2024-03-07",
+		"friday":     "2024-03-08",
+		"wed":        "2024-03-13",
+		"+3d":        "2024-03-09",
+		"+2w":        "2024-03-20",
+		"none":       "",
+		"":           "",
+	}
+	for input, want := range cases {
+		got, err := ParseRelativeDate(input, now)
+		if err != nil || got != want {
+			t.Fatalf("%q: expected %q, got %q (%v)", input, want, got, err)
+		}
+	}
+	for _, bad := range []string{"soon", "+d", "+3m", "2024-13-01", "-3d"} {
+		if _, err := ParseRelativeDate(bad, now); err == nil {
+			t.Fatalf("expected %q rejected", bad)
+		}
+	}
+}
+
+func TestFormatDueRelative(t *testing.T) {
+	now := time.Date(2024, 3, 6, 23, 30, 0, 0, time.UTC)
+	cases := map[string]string{
+		"2024-03-06": "due today",
+		"2024-03-07": "due tomorrow",
+		"2024-03-08": "due in 2 days",
+		"2024-03-05": "overdue by 1 day",
+		"2024-02-26": "overdue by 9 days",
+		"":           "",
+	}
+	for dueAt, want := range cases {
+		if got := FormatDueRelative(dueAt, now); got != want {
+			t.Fatalf("%q: expected %q, got %q", dueAt, want, got)
+		}
+	}
+}
+
+func TestSortReadyIssuesPutsOverdueFirst(t *testing.T) {
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	issues := []Issue{
+		{ID: "pb-later", Priority: 1, DueAt: "2024-04-01", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "pb-nodue", Priority: 1, CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "pb-soon", Priority: 1, DueAt: "2024-03-08", CreatedAt: "2024-01-02T00:00:00Z"},
+		{ID: "pb-late", Priority: 3, DueAt: "2024-03-01", CreatedAt: "2024-01-03T00:00:00Z"},
+		{ID: "pb-urgent", Priority: 0, CreatedAt: "2024-01-04T00:00:00Z"},
+	}
+	SortReadyIssues(issues, now)
+	want := []string{"pb-late", "pb-urgent", "pb-soon", "pb-later", "pb-nodue"}
+	for i, id := range want {
+		if issues[i].ID != id {
+			t.Fatalf("position %d: expected %s, got %s", i, id, issues[i].ID)
+		}
+	}
+}
+
+func TestDueEventSetsAndClearsDueDate(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-1", "Due", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewDueEvent("pb-1", "2024-02-01", "2024-01-01T00:00:01Z"),
+		NewCreateEvent("pb-2", "Cleared", "", "task", "2024-01-01T00:00:02Z", 2),
+		NewDueEvent("pb-2", "2024-02-01", "2024-01-01T00:00:03Z"),
+		NewDueEvent("pb-2", "", "2024-01-01T00:00:04Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	first, _, err := GetIssue(root, "pb-1")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	second, _, err := GetIssue(root, "pb-2")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if first.DueAt != "2024-02-01" || second.DueAt != "" {
+		t.Fatalf("unexpected due dates: %q, %q", first.DueAt, second.DueAt)
+	}
+}
@@ -0,0 +1,72 @@
+package pebbles
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// IssueDuplicates describes duplicate-of links around an issue.
+type IssueDuplicates struct {
+	// Canonical is the surviving issue when this issue is a duplicate, found by
+	// following duplicate-of links to the end of the chain; nil otherwise.
+	Canonical *Issue
+	// Duplicates lists issues marked as duplicates of this issue.
+	Duplicates []Issue
+}
+
+// GetIssueDuplicates returns the canonical issue and the duplicates for an ID.
+func GetIssueDuplicates(root, id string) (IssueDuplicates, error) {
+	if err := EnsureCache(root); err != nil {
+		return IssueDuplicates{}, err
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		return IssueDuplicates{}, err
+	}
+	defer func() { _ = db.Close() }()
+	resolvedID, err := resolveIssueID(db, id)
+	if err != nil {
+		return IssueDuplicates{}, err
+	}
+	var duplicates IssueDuplicates
+	canonicalID, err := followDuplicates(db, resolvedID)
+	if err != nil {
+		return IssueDuplicates{}, err
+	}
+	if canonicalID != resolvedID {
+		canonical, err := getIssueByID(db, canonicalID)
+		if err != nil {
+			return IssueDuplicates{}, err
+		}
+		duplicates.Canonical = &canonical
+	}
+	duplicateIDs, err := getDependents(db, resolvedID, DepTypeDuplicateOf)
+	if err != nil {
+		return IssueDuplicates{}, err
+	}
+	duplicates.Duplicates, err = loadIssuesByID(db, duplicateIDs)
+	if err != nil {
+		return IssueDuplicates{}, err
+	}
+	return duplicates, nil
+}
+
+// followDuplicates walks duplicate-of links to the surviving issue ID.
+func followDuplicates(db *sql.DB, id string) (string, error) {
+	current := id
+	visited := make(map[string]bool)
+	for {
+		if visited[current] {
+			return "", fmt.Errorf("duplicate-of cycle detected for %s", id)
+		}
+		visited[current] = true
+		targets, err := getDeps(db, current, DepTypeDuplicateOf)
+		if err != nil {
+			return "", err
+		}
+		if len(targets) == 0 {
+			return current, nil
+		}
+		current = targets[0]
+	}
+}
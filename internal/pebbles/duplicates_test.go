@@ -0,0 +1,49 @@
+package pebbles
+
+import "testing"
+
+// TestGetIssueDuplicatesFollowsChain verifies duplicate-of links resolve to the survivor.
+func TestGetIssueDuplicatesFollowsChain(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-a", "First report", "", "bug", "2024-01-01T00:00:00Z", 2),
+		NewCreateEvent("pb-b", "Second report", "", "bug", "2024-01-01T00:01:00Z", 2),
+		NewCreateEvent("pb-c", "Third report", "", "bug", "2024-01-01T00:02:00Z", 2),
+		NewDepAddEvent("pb-c", "pb-b", DepTypeDuplicateOf, "2024-01-01T00:03:00Z"),
+		NewDepAddEvent("pb-b", "pb-a", DepTypeDuplicateOf, "2024-01-01T00:04:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	duplicates, err := GetIssueDuplicates(root, "pb-c")
+	if err != nil {
+		t.Fatalf("get duplicates: %v", err)
+	}
+	if duplicates.Canonical == nil || duplicates.Canonical.ID != "pb-a" {
+		t.Fatalf("expected pb-c to resolve to pb-a, got %+v", duplicates.Canonical)
+	}
+	duplicates, err = GetIssueDuplicates(root, "pb-a")
+	if err != nil {
+		t.Fatalf("get duplicates for canonical: %v", err)
+	}
+	if duplicates.Canonical != nil {
+		t.Fatalf("expected pb-a to be canonical")
+	}
+	if len(duplicates.Duplicates) != 1 || duplicates.Duplicates[0].ID != "pb-b" {
+		t.Fatalf("expected pb-b as the direct duplicate, got %+v", duplicates.Duplicates)
+	}
+	// Duplicate links do not block readiness.
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 3 {
+		t.Fatalf("expected duplicate-of links to leave issues ready, got %d", len(ready))
+	}
+}
@@ -10,41 +10,76 @@ func NewCreateEvent(issueID, title, description, issueType, timestamp string, pr
 		"type":        issueType,
 		"priority":    fmt.Sprintf("%d", priority),
 	}
-	return Event{Type: EventTypeCreate, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	return newEvent(EventTypeCreate, issueID, timestamp, payload)
 }
 
 // NewTitleUpdatedEvent builds a title update event.
 func NewTitleUpdatedEvent(issueID, title, timestamp string) Event {
 	payload := map[string]string{"title": title}
-	return Event{Type: EventTypeTitleUpdated, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	return newEvent(EventTypeTitleUpdated, issueID, timestamp, payload)
 }
 
-// NewStatusEvent builds a status update event.
+// NewStatusEvent builds a status update event without a status category, so
+// replay treats only closed as done.
 func NewStatusEvent(issueID, status, timestamp string) Event {
+	return NewStatusEventWithCategory(issueID, status, "", timestamp)
+}
+
+// NewStatusEventWithCategory builds a status update event that records the
+// workflow category of the status, which tells replay whether it is done.
+func NewStatusEventWithCategory(issueID, status, category, timestamp string) Event {
 	payload := map[string]string{"status": status}
-	return Event{Type: EventTypeStatus, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	if category != "" {
+		payload["category"] = category
+	}
+	return newEvent(EventTypeStatus, issueID, timestamp, payload)
 }
 
 // NewUpdateEvent builds an issue field update event.
 func NewUpdateEvent(issueID, timestamp string, payload map[string]string) Event {
-	return Event{Type: EventTypeUpdate, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	return newEvent(EventTypeUpdate, issueID, timestamp, payload)
 }
 
 // NewCloseEvent builds a close event.
 func NewCloseEvent(issueID, timestamp string) Event {
-	return Event{Type: EventTypeClose, Timestamp: timestamp, IssueID: issueID, Payload: map[string]string{}}
+	return NewResolvedCloseEvent(issueID, "", "", timestamp)
 }
 
-// NewCommentEvent builds a comment event.
+// NewResolvedCloseEvent builds a close event carrying a resolution and reason.
+// Empty values are left out of the payload.
+func NewResolvedCloseEvent(issueID, resolution, reason, timestamp string) Event {
+	payload := map[string]string{}
+	if resolution != "" {
+		payload["resolution"] = resolution
+	}
+	if reason != "" {
+		payload["reason"] = reason
+	}
+	return newEvent(EventTypeClose, issueID, timestamp, payload)
+}
+
+// NewCommentEvent builds a comment event carrying a new comment ID.
 func NewCommentEvent(issueID, body, timestamp string) Event {
-	payload := map[string]string{"body": body}
-	return Event{Type: EventTypeComment, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	payload := map[string]string{"comment_id": newCommentID(), "body": body}
+	return newEvent(EventTypeComment, issueID, timestamp, payload)
+}
+
+// NewCommentEditEvent builds an event that replaces a comment body.
+func NewCommentEditEvent(issueID, commentID, body, timestamp string) Event {
+	payload := map[string]string{"comment_id": commentID, "body": body}
+	return newEvent(EventTypeCommentEdit, issueID, timestamp, payload)
+}
+
+// NewCommentDeleteEvent builds an event that removes a comment.
+func NewCommentDeleteEvent(issueID, commentID, timestamp string) Event {
+	payload := map[string]string{"comment_id": commentID}
+	return newEvent(EventTypeCommentDelete, issueID, timestamp, payload)
 }
 
 // NewRenameEvent builds a rename event for an issue ID change.
 func NewRenameEvent(issueID, newIssueID, timestamp string) Event {
 	payload := map[string]string{"new_id": newIssueID}
-	return Event{Type: EventTypeRename, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	return newEvent(EventTypeRename, issueID, timestamp, payload)
 }
 
 // NewDepAddEvent builds a dependency add event.
@@ -53,7 +88,7 @@ func NewDepAddEvent(issueID, dependsOn, depType, timestamp string) Event {
 		"depends_on": dependsOn,
 		"dep_type":   NormalizeDepType(depType),
 	}
-	return Event{Type: EventTypeDepAdd, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	return newEvent(EventTypeDepAdd, issueID, timestamp, payload)
 }
 
 // NewDepRemoveEvent builds a dependency removal event.
@@ -62,5 +97,103 @@ func NewDepRemoveEvent(issueID, dependsOn, depType, timestamp string) Event {
 		"depends_on": dependsOn,
 		"dep_type":   NormalizeDepType(depType),
 	}
-	return Event{Type: EventTypeDepRemove, Timestamp: timestamp, IssueID: issueID, Payload: payload}
+	return newEvent(EventTypeDepRemove, issueID, timestamp, payload)
+}
+
+// NewFieldSetEvent builds a custom field event; an empty value clears the field.
+func NewFieldSetEvent(issueID, field, value, timestamp string) Event {
+	payload := map[string]string{"field": field, "value": value}
+	return newEvent(EventTypeFieldSet, issueID, timestamp, payload)
+}
+
+// NewDueEvent builds a due date event; an empty dueAt clears the due date.
+func NewDueEvent(issueID, dueAt, timestamp string) Event {
+	payload := map[string]string{"due_at": dueAt}
+	return newEvent(EventTypeDue, issueID, timestamp, payload)
+}
+
+// NewDeferEvent builds an event deferring an issue until a YYYY-MM-DD date.
+func NewDeferEvent(issueID, until, timestamp string) Event {
+	payload := map[string]string{"until": until}
+	return newEvent(EventTypeDefer, issueID, timestamp, payload)
+}
+
+// NewUndeferEvent builds an event clearing an issue's deferral.
+func NewUndeferEvent(issueID, timestamp string) Event {
+	return newEvent(EventTypeUndefer, issueID, timestamp, map[string]string{})
+}
+
+// NewEstimateEvent builds an event setting an issue's estimate.
+func NewEstimateEvent(issueID, estimate, timestamp string) Event {
+	payload := map[string]string{"estimate": estimate}
+	return newEvent(EventTypeEstimate, issueID, timestamp, payload)
+}
+
+// NewTimerStartEvent builds an event starting a work timer.
+func NewTimerStartEvent(issueID, timestamp string) Event {
+	return newEvent(EventTypeTimerStart, issueID, timestamp, map[string]string{})
+}
+
+// NewWorkLogEvent builds an event logging minutes of work. A non-empty
+// startedAt marks the entry as the end of the running timer.
+func NewWorkLogEvent(issueID string, minutes int, startedAt, timestamp string) Event {
+	payload := map[string]string{"minutes": fmt.Sprintf("%d", minutes)}
+	if startedAt != "" {
+		payload["started_at"] = startedAt
+	}
+	return newEvent(EventTypeWorkLog, issueID, timestamp, payload)
+}
+
+// NewLabelAddEvent builds a label add event.
+func NewLabelAddEvent(issueID, label, timestamp string) Event {
+	payload := map[string]string{"label": label}
+	return newEvent(EventTypeLabelAdd, issueID, timestamp, payload)
+}
+
+// NewLabelRemoveEvent builds a label removal event.
+func NewLabelRemoveEvent(issueID, label, timestamp string) Event {
+	payload := map[string]string{"label": label}
+	return newEvent(EventTypeLabelRemove, issueID, timestamp, payload)
+}
+
+// NewAssignEvent builds an assignee change event; an empty assignee unassigns.
+func NewAssignEvent(issueID, assignee, timestamp string) Event {
+	payload := map[string]string{"assignee": assignee}
+	return newEvent(EventTypeAssign, issueID, timestamp, payload)
+}
+
+// NewClaimEvent builds a claim event that lasts until expiresAt.
+func NewClaimEvent(issueID, expiresAt, timestamp string) Event {
+	payload := map[string]string{"expires_at": expiresAt}
+	return newEvent(EventTypeClaim, issueID, timestamp, payload)
+}
+
+// NewReleaseEvent builds an event that gives back a claim.
+func NewReleaseEvent(issueID, timestamp string) Event {
+	return newEvent(EventTypeRelease, issueID, timestamp, map[string]string{})
+}
+
+// NewDeleteEvent builds an event that hides an issue, with an optional reason.
+func NewDeleteEvent(issueID, reason, timestamp string) Event {
+	payload := map[string]string{}
+	if reason != "" {
+		payload["reason"] = reason
+	}
+	return newEvent(EventTypeDelete, issueID, timestamp, payload)
+}
+
+// NewUndeleteEvent builds an event that restores a deleted issue.
+func NewUndeleteEvent(issueID, timestamp string) Event {
+	return newEvent(EventTypeUndelete, issueID, timestamp, map[string]string{})
+}
+
+// newEvent builds an event stamped with the current schema version.
+func newEvent(eventType, issueID, timestamp string, payload map[string]string) Event {
+	return Event{
+		Version:   EventSchemaVersion,
+		Type:      eventType,
+		Timestamp: timestamp,
+		IssueID:   issueID,
+		Payload:   payload,
+	}
 }
@@ -0,0 +1,118 @@
+package pebbles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// payloadKey names a payload key an event type requires.
+type payloadKey struct {
+	name string
+	// mayBeEmpty accepts a present but empty value, which clears the field.
+	mayBeEmpty bool
+}
+
+// eventSpec describes the payload an event type needs to be applied.
+type eventSpec struct {
+	keys []payloadKey
+	// check validates the payload beyond its required keys, if set.
+	check func(Event) error
+}
+
+// eventSpecs lists every event type this version of pb can apply. Replay
+// validates payloads against it before applying an event, and pb doctor
+// reports events that would fail the same checks.
+var eventSpecs = map[string]eventSpec{
+	EventTypeCreate:        {keys: []payloadKey{{name: "title"}}},
+	EventTypeTitleUpdated:  {keys: []payloadKey{{name: "title"}}},
+	EventTypeStatus:        {keys: []payloadKey{{name: "status"}}},
+	EventTypeUpdate:        {check: checkUpdatePayload},
+	EventTypeClose:         {},
+	EventTypeComment:       {keys: []payloadKey{{name: "body"}}},
+	EventTypeRename:        {keys: []payloadKey{{name: "new_id"}}},
+	EventTypeDepAdd:        {keys: []payloadKey{{name: "depends_on"}}},
+	EventTypeDepRemove:     {keys: []payloadKey{{name: "depends_on"}}},
+	EventTypeLabelAdd:      {keys: []payloadKey{{name: "label"}}},
+	EventTypeLabelRemove:   {keys: []payloadKey{{name: "label"}}},
+	EventTypeAssign:        {keys: []payloadKey{{name: "assignee", mayBeEmpty: true}}},
+	EventTypeClaim:         {keys: []payloadKey{{name: "expires_at"}}, check: checkClaimActor},
+	EventTypeRelease:       {},
+	EventTypeDelete:        {},
+	EventTypeUndelete:      {},
+	EventTypeCommentEdit:   {keys: []payloadKey{{name: "comment_id"}, {name: "body"}}},
+	EventTypeCommentDelete: {keys: []payloadKey{{name: "comment_id"}}},
+	EventTypeFieldSet:      {keys: []payloadKey{{name: "field"}}},
+	EventTypeDue:           {keys: []payloadKey{{name: "due_at", mayBeEmpty: true}}},
+	EventTypeDefer:         {keys: []payloadKey{{name: "until"}}},
+	EventTypeUndefer:       {},
+	EventTypeEstimate:      {keys: []payloadKey{{name: "estimate", mayBeEmpty: true}}},
+	EventTypeTimerStart:    {},
+	EventTypeWorkLog:       {keys: []payloadKey{{name: "minutes"}}, check: checkWorkLogMinutes},
+}
+
+// IsKnownEventType reports whether this version of pb can apply an event type.
+func IsKnownEventType(eventType string) bool {
+	_, ok := eventSpecs[eventType]
+	return ok
+}
+
+// payloadError describes an event payload that fails its type's spec.
+type payloadError struct {
+	eventType string
+	// key is the offending payload key, or empty when no single key is at fault.
+	key     string
+	problem string
+}
+
+// Error implements error.
+func (err *payloadError) Error() string {
+	return fmt.Sprintf("%s event %s", err.eventType, err.problem)
+}
+
+// validateEventPayload checks an event of a known type against eventSpecs.
+// Required keys must hold a non-blank value unless they may be empty.
+func validateEventPayload(event Event) error {
+	spec, ok := eventSpecs[event.Type]
+	if !ok {
+		return nil
+	}
+	for _, key := range spec.keys {
+		value, present := event.Payload[key.name]
+		if !present || (!key.mayBeEmpty && strings.TrimSpace(value) == "") {
+			return &payloadError{eventType: event.Type, key: key.name, problem: "missing " + key.name}
+		}
+	}
+	if spec.check != nil {
+		return spec.check(event)
+	}
+	return nil
+}
+
+// checkUpdatePayload requires an update event to change at least one field.
+func checkUpdatePayload(event Event) error {
+	for _, key := range []string{"type", "description", "priority"} {
+		if _, ok := event.Payload[key]; ok {
+			return nil
+		}
+	}
+	return &payloadError{eventType: event.Type, problem: "missing fields"}
+}
+
+// checkClaimActor requires a claim to name its claimant.
+func checkClaimActor(event Event) error {
+	if strings.TrimSpace(event.Actor) == "" {
+		return &payloadError{eventType: event.Type, problem: "missing actor"}
+	}
+	return nil
+}
+
+// checkWorkLogMinutes requires a work_log event to record positive minutes.
+func checkWorkLogMinutes(event Event) error {
+	minutes, err := strconv.Atoi(strings.TrimSpace(event.Payload["minutes"]))
+	if err != nil || minutes <= 0 {
+		return &payloadError{eventType: event.Type, key: "minutes",
+			problem: fmt.Sprintf("has invalid minutes %q", event.Payload["minutes"])}
+	}
+	return nil
+}
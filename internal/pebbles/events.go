@@ -1,7 +1,7 @@
 package pebbles
 
 import (
-	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,22 +9,137 @@ import (
 
 // AppendEvent appends a single event to the events log.
 func AppendEvent(root string, event Event) error {
-	path := EventsPath(root)
-	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	return AppendEvents(root, []Event{event})
+}
+
+// AppendEvents appends a batch of events to the events log in a single write.
+// The write happens under the project lock, and the log is truncated back to
+// its previous size if the write fails so a partial batch is never left behind.
+// Events without a clock are stamped with consecutive Lamport clocks following
+// the highest clock already in the log, so the batch replays in order, and
+// events without an actor are stamped with ResolveActor. Missing
+// .pebbles/.gitignore entries are added before the first write.
+func AppendEvents(root string, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return WithLock(root, func() error {
+		// Projects initialized before an entry was added (the lock file itself,
+		// views.local.json) pick it up here rather than only at init.
+		if err := ensureGitignore(root); err != nil {
+			return err
+		}
+		clock, err := currentLogClock(root)
+		if err != nil {
+			return err
+		}
+		actor := ResolveActor(root)
+		var buffer bytes.Buffer
+		for _, event := range events {
+			if event.Actor == "" {
+				event.Actor = actor
+			}
+			if event.Clock <= 0 {
+				clock++
+				event.Clock = clock
+			} else if event.Clock > clock {
+				clock = event.Clock
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("marshal event: %w", err)
+			}
+			buffer.Write(data)
+			buffer.WriteByte('\n')
+		}
+		return appendLogData(EventsPath(root), buffer.Bytes())
+	})
+}
+
+// currentLogClock returns the highest event clock in the log; callers must
+// hold the lock. It starts from the cache checkpoint when the log still begins
+// with the consumed prefix and scans only the rest, so appending never replays
+// the log and still works when the log cannot be replayed.
+func currentLogClock(root string) (int64, error) {
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		return 0, fmt.Errorf("read events log: %w", err)
+	}
+	var clock, offset int64
+	if checkpoint, ok := cachedReplayCheckpoint(root); ok && checkpoint.matches(data) {
+		clock, offset = checkpoint.MaxClock, checkpoint.Offset
+	}
+	for _, line := range splitLogLines(data[offset:]) {
+		// Lines that do not decode (conflict markers, damage) carry no clock;
+		// doctor and replay report them.
+		var stamped struct {
+			Clock int64 `json:"clock"`
+		}
+		if json.Unmarshal(line.Text, &stamped) == nil && stamped.Clock > clock {
+			clock = stamped.Clock
+		}
+	}
+	return clock, nil
+}
+
+// cachedReplayCheckpoint reads the checkpoint of an existing cache without
+// creating or rebuilding it; any problem just means there is no checkpoint.
+func cachedReplayCheckpoint(root string) (replayCheckpoint, bool) {
+	path := DBPath(root)
+	if _, err := os.Stat(path); err != nil {
+		return replayCheckpoint{}, false
+	}
+	db, err := openDB(path)
+	if err != nil {
+		return replayCheckpoint{}, false
+	}
+	defer func() { _ = db.Close() }()
+	checkpoint, ok, err := loadReplayCheckpoint(db)
+	if err != nil {
+		return replayCheckpoint{}, false
+	}
+	return checkpoint, ok
+}
+
+// appendLogData writes encoded events to the log, rolling back on failure.
+func appendLogData(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_RDWR, 0600)
 	if err != nil {
 		return fmt.Errorf("open events log: %w", err)
 	}
 	defer func() { _ = file.Close() }()
-	data, err := json.Marshal(event)
+	info, err := file.Stat()
 	if err != nil {
-		return fmt.Errorf("marshal event: %w", err)
+		return fmt.Errorf("stat events log: %w", err)
+	}
+	size := info.Size()
+	// Terminate a dangling last line so the batch starts on its own line.
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, size-1); err != nil {
+			return fmt.Errorf("read events log: %w", err)
+		}
+		if last[0] != '\n' {
+			data = append([]byte{'\n'}, data...)
+		}
+	}
+	if _, err := file.Write(data); err != nil {
+		return rollbackAppend(file, size, fmt.Errorf("append events: %w", err))
 	}
-	if _, err := file.Write(append(data, '\n')); err != nil {
-		return fmt.Errorf("append event: %w", err)
+	if err := file.Sync(); err != nil {
+		return rollbackAppend(file, size, fmt.Errorf("sync events log: %w", err))
 	}
 	return nil
 }
 
+// rollbackAppend truncates the log to its pre-append size and returns cause.
+func rollbackAppend(file *os.File, size int64, cause error) error {
+	if err := file.Truncate(size); err != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+	}
+	return cause
+}
+
 // LoadEvents reads all events from the events log.
 func LoadEvents(root string) ([]Event, error) {
 	return readEvents(EventsPath(root))
@@ -32,28 +147,62 @@ func LoadEvents(root string) ([]Event, error) {
 
 // readEvents reads events from a JSONL file path.
 func readEvents(path string) ([]Event, error) {
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("open events log: %w", err)
 	}
-	defer func() { _ = file.Close() }()
-	// Scan the file line by line to decode JSONL records.
-	scanner := bufio.NewScanner(file)
-	var events []Event
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-		// Decode each event line into the Event struct.
-		var event Event
-		if err := json.Unmarshal(line, &event); err != nil {
-			return nil, fmt.Errorf("parse event: %w", err)
-		}
-		events = append(events, event)
+	return parseEvents(data)
+}
+
+// eventRecord mirrors Event with a loosely typed payload, so events written
+// by newer clients with non-string payload values still decode.
+type eventRecord struct {
+	Version   int                        `json:"version"`
+	Clock     int64                      `json:"clock"`
+	Type      string                     `json:"type"`
+	Timestamp string                     `json:"timestamp"`
+	IssueID   string                     `json:"issue_id"`
+	Actor     string                     `json:"actor"`
+	Payload   map[string]json.RawMessage `json:"payload"`
+}
+
+// decodeEvent decodes a single JSONL event line.
+// Non-string payload values are kept as their compact JSON text.
+func decodeEvent(line []byte) (Event, error) {
+	var record eventRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return Event{}, err
+	}
+	event := Event{
+		Version:   record.Version,
+		Clock:     record.Clock,
+		Type:      record.Type,
+		Timestamp: record.Timestamp,
+		IssueID:   record.IssueID,
+		Actor:     record.Actor,
+	}
+	if record.Payload != nil {
+		event.Payload = make(map[string]string, len(record.Payload))
+	}
+	for key, raw := range record.Payload {
+		event.Payload[key] = payloadValueString(raw)
+	}
+	return event, nil
+}
+
+// payloadValueString converts a raw payload value into its string form.
+func payloadValueString(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	trimmed := bytes.TrimSpace(raw)
+	if bytes.Equal(trimmed, []byte("null")) {
+		return ""
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scan events log: %w", err)
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, trimmed); err != nil {
+		return string(trimmed)
 	}
-	return events, nil
+	return compact.String()
 }
@@ -0,0 +1,178 @@
+package pebbles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FieldTypeString accepts any text.
+	FieldTypeString = "string"
+	// FieldTypeInt accepts whole numbers.
+	FieldTypeInt = "int"
+	// FieldTypeEnum accepts one of the declared values.
+	FieldTypeEnum = "enum"
+	// FieldTypeDate accepts YYYY-MM-DD dates.
+	FieldTypeDate = "date"
+)
+
+// dateLayout is the stored format for date fields and due dates.
+const dateLayout = "2006-01-02"
+
+// reservedFieldNames are built-in issue fields a custom field cannot shadow,
+// including every field --where, --sort, and view columns resolve first.
+var reservedFieldNames = map[string]bool{
+	"id": true, "title": true, "description": true, "type": true, "status": true,
+	"priority": true, "assignee": true, "labels": true, "resolution": true, "due": true,
+	"created": true, "updated": true, "closed": true, "deferred": true, "logged": true,
+	"estimate": true, "label": true, "parent": true, "children": true,
+}
+
+// FieldConfig declares one custom field in config.json. Values lists the
+// allowed values of an enum field.
+type FieldConfig struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Values []string `json:"values,omitempty"`
+}
+
+// CustomFields is the validated set of custom fields a project declares.
+type CustomFields struct {
+	fields []FieldConfig
+	index  map[string]int
+}
+
+// NewCustomFields validates custom field declarations.
+func NewCustomFields(fields []FieldConfig) (CustomFields, error) {
+	customFields := CustomFields{index: make(map[string]int, len(fields))}
+	for _, field := range fields {
+		name := strings.ToLower(strings.TrimSpace(field.Name))
+		if name == "" {
+			return CustomFields{}, fmt.Errorf("field name is required")
+		}
+		if strings.ContainsAny(name, ",= \t\n") {
+			return CustomFields{}, fmt.Errorf("field %q cannot contain commas, equals signs, or spaces", field.Name)
+		}
+		if reservedFieldNames[name] {
+			return CustomFields{}, fmt.Errorf("field %s is a built-in issue field", name)
+		}
+		if _, ok := customFields.index[name]; ok {
+			return CustomFields{}, fmt.Errorf("field %s is declared twice", name)
+		}
+		fieldType := strings.ToLower(strings.TrimSpace(field.Type))
+		var values []string
+		switch fieldType {
+		case FieldTypeString, FieldTypeInt, FieldTypeDate:
+			if len(field.Values) > 0 {
+				return CustomFields{}, fmt.Errorf("field %s: values only apply to enum fields", name)
+			}
+		case FieldTypeEnum:
+			for _, value := range field.Values {
+				value = strings.TrimSpace(value)
+				if value == "" {
+					return CustomFields{}, fmt.Errorf("field %s has an empty enum value", name)
+				}
+				values = append(values, value)
+			}
+			if len(values) == 0 {
+				return CustomFields{}, fmt.Errorf("field %s is an enum without values", name)
+			}
+		default:
+			return CustomFields{}, fmt.Errorf("field %s has unknown type %q (use string, int, enum, or date)", name, field.Type)
+		}
+		customFields.index[name] = len(customFields.fields)
+		customFields.fields = append(customFields.fields, FieldConfig{Name: name, Type: fieldType, Values: values})
+	}
+	return customFields, nil
+}
+
+// LoadCustomFields reads the custom fields from config.json. Projects without
+// a config declare none.
+func LoadCustomFields(root string) (CustomFields, error) {
+	cfg, err := LoadConfig(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return CustomFields{}, nil
+	}
+	if err != nil {
+		return CustomFields{}, err
+	}
+	customFields, err := NewCustomFields(cfg.Fields)
+	if err != nil {
+		return CustomFields{}, fmt.Errorf("config fields: %w", err)
+	}
+	return customFields, nil
+}
+
+// Names returns the declared field names in config order.
+func (customFields CustomFields) Names() []string {
+	names := make([]string, 0, len(customFields.fields))
+	for _, field := range customFields.fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// Lookup returns the declaration for a field, matching case-insensitively.
+func (customFields CustomFields) Lookup(name string) (FieldConfig, bool) {
+	index, ok := customFields.index[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return FieldConfig{}, false
+	}
+	return customFields.fields[index], true
+}
+
+// ParseValue validates a value for a declared field and returns the field
+// name with the value in stored form. An empty value clears the field.
+func (customFields CustomFields) ParseValue(name, input string) (string, string, error) {
+	field, ok := customFields.Lookup(name)
+	if !ok {
+		if len(customFields.fields) == 0 {
+			return "", "", fmt.Errorf("unknown field %q (config.json declares no fields)", name)
+		}
+		return "", "", fmt.Errorf("unknown field %q (valid: %s)", name, strings.Join(customFields.Names(), ", "))
+	}
+	value := strings.TrimSpace(input)
+	if value == "" {
+		return field.Name, "", nil
+	}
+	switch field.Type {
+	case FieldTypeInt:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return "", "", fmt.Errorf("field %s: %q is not a whole number", field.Name, input)
+		}
+		value = strconv.Itoa(parsed)
+	case FieldTypeEnum:
+		matched := ""
+		for _, allowed := range field.Values {
+			if strings.EqualFold(allowed, value) {
+				matched = allowed
+				break
+			}
+		}
+		if matched == "" {
+			return "", "", fmt.Errorf("field %s: %q is not one of %s", field.Name, input, strings.Join(field.Values, ", "))
+		}
+		value = matched
+	case FieldTypeDate:
+		parsed, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return "", "", fmt.Errorf("field %s: %q is not a YYYY-MM-DD date", field.Name, input)
+		}
+		value = parsed.Format(dateLayout)
+	}
+	return field.Name, value, nil
+}
+
+// ParseAssignment parses a "name=value" flag and validates the value.
+func (customFields CustomFields) ParseAssignment(input string) (string, string, error) {
+	name, value, ok := strings.Cut(input, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return "", "", fmt.Errorf("invalid field %q: use name=value", input)
+	}
+	return customFields.ParseValue(name, value)
+}
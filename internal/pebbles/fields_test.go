@@ -0,0 +1,137 @@
+package pebbles
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCustomFieldsParseValues(t *testing.T) {
+	fields, err := NewCustomFields([]FieldConfig{
+		{Name: "Component", Type: "string"},
+		{Name: "seats", Type: "int"},
+		{Name: "severity", Type: "enum", Values: []string{"low", "High"}},
+		{Name: "launch", Type: "date"},
+	})
+	if err != nil {
+		t.Fatalf("new custom fields: %v", err)
+	}
+	cases := []struct {
+		input, name, value string
+	}{
+		{"component= API gateway ", "component", "API gateway"},
+		{"SEATS=+12", "seats", "12"},
+		{"severity=high", "severity", "High"},
+		{"launch=2024-03-01", "launch", "2024-03-01"},
+		{"launch=", "launch", ""},
+	}
+	for _, tc := range cases {
+		name, value, err := fields.ParseAssignment(tc.input)
+		if err != nil || name != tc.name || value != tc.value {
+			t.Fatalf("%s: got %q=%q (%v)", tc.input, name, value, err)
+		}
+	}
+	for _, bad := range []string{"seats=many", "severity=urgent", "launch=03/01/2024", "owner=me", "component"} {
+		if _, _, err := fields.ParseAssignment(bad); err == nil {
+			t.Fatalf("expected %q rejected", bad)
+		}
+	}
+	for _, decl := range [][]FieldConfig{
+		{{Name: "status", Type: "string"}},
+		{{Name: "tier", Type: "enum"}},
+		{{Name: "tier", Type: "bool"}},
+		{{Name: "a b", Type: "string"}},
+	} {
+		if _, err := NewCustomFields(decl); err == nil {
+			t.Fatalf("expected declaration %+v rejected", decl)
+		}
+	}
+	// Built-ins win when --where, --sort, or a view column names a field, so
+	// a custom field with the same name would be unreachable.
+	builtins := append(SortFieldNames(), ViewColumns...)
+	for name := range whereBuiltinFields {
+		builtins = append(builtins, name)
+	}
+	for _, name := range builtins {
+		if _, err := NewCustomFields([]FieldConfig{{Name: name, Type: "string"}}); err == nil || !strings.Contains(err.Error(), "built-in") {
+			t.Fatalf("expected field %s rejected as built-in, got %v", name, err)
+		}
+	}
+}
+
+func TestFieldSetEventsFollowRenames(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-old", "Fielded", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewFieldSetEvent("pb-old", "customer", "Acme", "2024-01-01T00:00:01Z"),
+		NewFieldSetEvent("pb-old", "severity", "low", "2024-01-01T00:00:02Z"),
+		NewRenameEvent("pb-old", "pb-new", "2024-01-01T00:00:03Z"),
+		NewFieldSetEvent("pb-new", "severity", "high", "2024-01-01T00:00:04Z"),
+		NewFieldSetEvent("pb-new", "customer", "", "2024-01-01T00:00:05Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-new")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if len(issue.Fields) != 1 || issue.Fields["severity"] != "high" {
+		t.Fatalf("unexpected fields: %+v", issue.Fields)
+	}
+	if issue.UpdatedAt != "2024-01-01T00:00:05Z" {
+		t.Fatalf("expected field_set to touch updated_at, got %s", issue.UpdatedAt)
+	}
+	report, err := CheckEventLog(root)
+	if err != nil {
+		t.Fatalf("check event log: %v", err)
+	}
+	if len(report.Problems) != 0 {
+		t.Fatalf("expected clean log, got %+v", report.Problems)
+	}
+}
+
+func TestFullReplayResetsFields(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-old", "Fielded", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewFieldSetEvent("pb-old", "customer", "Acme", "2024-01-01T00:00:01Z"),
+		NewRenameEvent("pb-old", "pb-new", "2024-01-01T00:00:02Z"),
+		NewTitleUpdatedEvent("pb-new", "Dropped", "2024-01-01T00:00:03Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	// Drop the last line so the checkpoint no longer matches and the next
+	// rebuild replays the whole log over the existing cache file.
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		t.Fatalf("read events log: %v", err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if err := os.WriteFile(EventsPath(root), []byte(strings.Join(lines[:3], "")), 0600); err != nil {
+		t.Fatalf("rewrite events log: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache after rewrite: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-new")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.Title != "Fielded" || issue.Fields["customer"] != "Acme" {
+		t.Fatalf("unexpected issue after full replay: %+v", issue)
+	}
+}
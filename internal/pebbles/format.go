@@ -38,20 +38,6 @@ func PriorityLabel(priority int) string {
 	return fmt.Sprintf("P%d", priority)
 }
 
-// StatusIcon returns the display icon for a status.
-func StatusIcon(status string) string {
-	switch status {
-	case StatusOpen:
-		return "○"
-	case StatusInProgress:
-		return "◐"
-	case StatusClosed:
-		return "●"
-	default:
-		return "○"
-	}
-}
-
 // StatusLabel formats a status for display in headers.
 func StatusLabel(status string) string {
 	switch status {
@@ -66,6 +52,21 @@ func StatusLabel(status string) string {
 	}
 }
 
+// ParseResolution validates a close resolution, accepting "wont-fix" style
+// spellings. An empty input returns an empty resolution.
+func ParseResolution(input string) (string, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(input))
+	if trimmed == "" {
+		return "", nil
+	}
+	normalized := strings.NewReplacer("-", "", "_", "", "'", "", " ", "").Replace(trimmed)
+	switch normalized {
+	case ResolutionDone, ResolutionWontFix, ResolutionDuplicate, ResolutionObsolete:
+		return normalized, nil
+	}
+	return "", fmt.Errorf("invalid resolution %q: use done, wontfix, duplicate, or obsolete", input)
+}
+
 // parsePriority defaults missing or invalid values to DefaultPriority.
 func parsePriority(input string) int {
 	priority, err := ParsePriority(input)
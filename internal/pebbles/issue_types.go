@@ -0,0 +1,223 @@
+package pebbles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RequiredFieldDescription is the built-in issue field a type can require;
+// types can also require declared custom fields.
+const RequiredFieldDescription = "description"
+
+// IssueTypeColors lists the color names an issue type can use in config.json.
+var IssueTypeColors = []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// IssueTypeConfig declares one issue type in config.json.
+type IssueTypeConfig struct {
+	Name            string   `json:"name"`
+	Icon            string   `json:"icon,omitempty"`
+	Color           string   `json:"color,omitempty"`
+	DefaultPriority string   `json:"default_priority,omitempty"`
+	Required        []string `json:"required,omitempty"`
+}
+
+// IssueTypes is the validated set of issue types. With no declared types any
+// non-empty type is accepted, as before types were configurable.
+type IssueTypes struct {
+	types []IssueTypeConfig
+	index map[string]int
+}
+
+// NewIssueTypes validates issue type declarations. Required fields must be
+// the description or one of the declared custom fields.
+func NewIssueTypes(types []IssueTypeConfig, customFields CustomFields) (IssueTypes, error) {
+	issueTypes := IssueTypes{index: make(map[string]int, len(types))}
+	for _, issueType := range types {
+		name := strings.ToLower(strings.TrimSpace(issueType.Name))
+		if name == "" {
+			return IssueTypes{}, fmt.Errorf("issue type name is required")
+		}
+		if strings.ContainsAny(name, ", \t\n") {
+			return IssueTypes{}, fmt.Errorf("issue type %q cannot contain commas or spaces", issueType.Name)
+		}
+		if _, ok := issueTypes.index[name]; ok {
+			return IssueTypes{}, fmt.Errorf("issue type %s is declared twice", name)
+		}
+		color := strings.ToLower(strings.TrimSpace(issueType.Color))
+		if color != "" && !containsString(IssueTypeColors, color) {
+			return IssueTypes{}, fmt.Errorf("issue type %s has unknown color %q (use %s)", name, issueType.Color, strings.Join(IssueTypeColors, ", "))
+		}
+		defaultPriority := strings.TrimSpace(issueType.DefaultPriority)
+		if defaultPriority != "" {
+			priority, err := ParsePriority(defaultPriority)
+			if err != nil {
+				return IssueTypes{}, fmt.Errorf("issue type %s default priority: %w", name, err)
+			}
+			defaultPriority = PriorityLabel(priority)
+		}
+		required := make([]string, 0, len(issueType.Required))
+		for _, field := range issueType.Required {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if _, ok := customFields.Lookup(field); field != RequiredFieldDescription && !ok {
+				return IssueTypes{}, fmt.Errorf("issue type %s requires unknown field %q", name, field)
+			}
+			required = append(required, field)
+		}
+		issueTypes.index[name] = len(issueTypes.types)
+		issueTypes.types = append(issueTypes.types, IssueTypeConfig{
+			Name:            name,
+			Icon:            strings.TrimSpace(issueType.Icon),
+			Color:           color,
+			DefaultPriority: defaultPriority,
+			Required:        required,
+		})
+	}
+	return issueTypes, nil
+}
+
+// LoadIssueTypes reads the issue types from config.json. Projects without a
+// config or without declared types accept any type.
+func LoadIssueTypes(root string) (IssueTypes, error) {
+	cfg, err := LoadConfig(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return IssueTypes{}, nil
+	}
+	if err != nil {
+		return IssueTypes{}, err
+	}
+	customFields, err := NewCustomFields(cfg.Fields)
+	if err != nil {
+		return IssueTypes{}, fmt.Errorf("config fields: %w", err)
+	}
+	issueTypes, err := NewIssueTypes(cfg.IssueTypes, customFields)
+	if err != nil {
+		return IssueTypes{}, fmt.Errorf("config issue_types: %w", err)
+	}
+	return issueTypes, nil
+}
+
+// Restricted reports whether the project declares its issue types.
+func (issueTypes IssueTypes) Restricted() bool {
+	return len(issueTypes.types) > 0
+}
+
+// Names returns the declared type names in config order.
+func (issueTypes IssueTypes) Names() []string {
+	names := make([]string, 0, len(issueTypes.types))
+	for _, issueType := range issueTypes.types {
+		names = append(names, issueType.Name)
+	}
+	return names
+}
+
+// Lookup returns the declaration for a type, matching case-insensitively.
+func (issueTypes IssueTypes) Lookup(name string) (IssueTypeConfig, bool) {
+	index, ok := issueTypes.index[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return IssueTypeConfig{}, false
+	}
+	return issueTypes.types[index], true
+}
+
+// ParseType validates user input against the declared types and returns the
+// declared name. Unknown types get a spelling suggestion when one is close.
+func (issueTypes IssueTypes) ParseType(input string) (string, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return "", fmt.Errorf("type cannot be empty")
+	}
+	if !issueTypes.Restricted() {
+		return trimmed, nil
+	}
+	if issueType, ok := issueTypes.Lookup(trimmed); ok {
+		return issueType.Name, nil
+	}
+	names := issueTypes.Names()
+	if suggestion := closestString(strings.ToLower(trimmed), names); suggestion != "" {
+		return "", fmt.Errorf("unknown issue type %q (did you mean %s? valid: %s)", input, suggestion, strings.Join(names, ", "))
+	}
+	return "", fmt.Errorf("unknown issue type %q (valid: %s)", input, strings.Join(names, ", "))
+}
+
+// DefaultPriority returns the type's default priority, or P2 when none is set.
+func (issueTypes IssueTypes) DefaultPriority(name string) int {
+	if issueType, ok := issueTypes.Lookup(name); ok && issueType.DefaultPriority != "" {
+		if priority, err := ParsePriority(issueType.DefaultPriority); err == nil {
+			return priority
+		}
+	}
+	return 2
+}
+
+// CheckRequired reports the first required field left empty for a type.
+// Fields holds the description and custom field values the issue will have
+// after the change.
+func (issueTypes IssueTypes) CheckRequired(name string, fields map[string]string) error {
+	issueType, ok := issueTypes.Lookup(name)
+	if !ok {
+		return nil
+	}
+	for _, field := range issueType.Required {
+		if strings.TrimSpace(fields[field]) == "" {
+			return fmt.Errorf("issue type %s requires %s", issueType.Name, field)
+		}
+	}
+	return nil
+}
+
+// containsString reports whether a slice holds a value.
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// closestString returns the candidate within a small edit distance of input,
+// or "" when nothing is close enough to be a likely typo.
+func closestString(input string, candidates []string) string {
+	best := ""
+	bestDistance := len(input)/3 + 1
+	if bestDistance > 3 {
+		bestDistance = 3
+	}
+	for _, candidate := range candidates {
+		if distance := editDistance(input, candidate); distance <= bestDistance {
+			if best == "" || distance < editDistance(input, best) {
+				best = candidate
+			}
+		}
+	}
+	return best
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment)
+// distance, so swapped letters like "bgu" count as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,78 @@
+package pebbles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewIssueTypesValidatesDeclarations(t *testing.T) {
+	cases := []struct {
+		name  string
+		types []IssueTypeConfig
+		want  string
+	}{
+		{"empty name", []IssueTypeConfig{{Name: " "}}, "name is required"},
+		{"duplicate", []IssueTypeConfig{{Name: "bug"}, {Name: "Bug"}}, "declared twice"},
+		{"color", []IssueTypeConfig{{Name: "bug", Color: "orange"}}, "unknown color"},
+		{"priority", []IssueTypeConfig{{Name: "bug", DefaultPriority: "P9"}}, "default priority"},
+		{"required", []IssueTypeConfig{{Name: "bug", Required: []string{"owner"}}}, "unknown field"},
+	}
+	for _, tc := range cases {
+		if _, err := NewIssueTypes(tc.types, CustomFields{}); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestIssueTypesParseAndRequire(t *testing.T) {
+	var free IssueTypes
+	if got, err := free.ParseType(" Whatever "); err != nil || got != "Whatever" {
+		t.Fatalf("expected free-form type accepted, got %q (%v)", got, err)
+	}
+	issueTypes, err := NewIssueTypes([]IssueTypeConfig{
+		{Name: "task"},
+		{Name: "Bug", Color: "Red", DefaultPriority: "1", Required: []string{"Description"}},
+		{Name: "feature"},
+	}, CustomFields{})
+	if err != nil {
+		t.Fatalf("new issue types: %v", err)
+	}
+	if got, err := issueTypes.ParseType("BUG"); err != nil || got != "bug" {
+		t.Fatalf("expected bug, got %q (%v)", got, err)
+	}
+	if _, err := issueTypes.ParseType("bgu"); err == nil || !strings.Contains(err.Error(), "did you mean bug?") {
+		t.Fatalf("expected suggestion for bgu, got %v", err)
+	}
+	if _, err := issueTypes.ParseType("incident"); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Fatalf("expected unknown type without suggestion, got %v", err)
+	}
+	if got := issueTypes.DefaultPriority("bug"); got != 1 {
+		t.Fatalf("expected bug default priority 1, got %d", got)
+	}
+	if got := issueTypes.DefaultPriority("task"); got != 2 {
+		t.Fatalf("expected task default priority 2, got %d", got)
+	}
+	if err := issueTypes.CheckRequired("bug", map[string]string{RequiredFieldDescription: " "}); err == nil {
+		t.Fatalf("expected bug without description rejected")
+	}
+	if err := issueTypes.CheckRequired("bug", map[string]string{RequiredFieldDescription: "Steps"}); err != nil {
+		t.Fatalf("expected bug with description accepted, got %v", err)
+	}
+}
+
+func TestBeadsImportMatchesDeclaredTypes(t *testing.T) {
+	issueTypes, err := NewIssueTypes([]IssueTypeConfig{{Name: "task"}, {Name: "bug"}}, CustomFields{})
+	if err != nil {
+		t.Fatalf("new issue types: %v", err)
+	}
+	var warnings []string
+	if got := normalizeBeadsIssueType("Bug", "bd-1", issueTypes, &warnings); got != "bug" {
+		t.Fatalf("expected bug, got %q", got)
+	}
+	if got := normalizeBeadsIssueType("molecule", "bd-2", issueTypes, &warnings); got != "molecule" {
+		t.Fatalf("expected undeclared type kept, got %q", got)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "bd-2") {
+		t.Fatalf("expected one warning for bd-2, got %v", warnings)
+	}
+}
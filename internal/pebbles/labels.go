@@ -0,0 +1,31 @@
+package pebbles
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NormalizeLabel lowercases and validates a label name.
+// Labels are stored comma-joined in the cache and listed as comma-separated
+// filters, so commas and whitespace are rejected.
+func NormalizeLabel(label string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(label))
+	if normalized == "" {
+		return "", fmt.Errorf("label is required")
+	}
+	if strings.ContainsRune(normalized, ',') || strings.IndexFunc(normalized, unicode.IsSpace) >= 0 {
+		return "", fmt.Errorf("invalid label %q: labels cannot contain commas or spaces", label)
+	}
+	return normalized, nil
+}
+
+// HasLabel reports whether an issue carries the given normalized label.
+func (issue Issue) HasLabel(label string) bool {
+	for _, existing := range issue.Labels {
+		if existing == label {
+			return true
+		}
+	}
+	return false
+}
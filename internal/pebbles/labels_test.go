@@ -0,0 +1,73 @@
+package pebbles
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestLabelEventsReplay verifies labels are added, removed, and follow renames.
+func TestLabelEventsReplay(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-aaa", "Labelled", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewLabelAddEvent("pb-aaa", "frontend", "2024-01-01T00:01:00Z"),
+		NewLabelAddEvent("pb-aaa", "bug", "2024-01-01T00:02:00Z"),
+		NewLabelAddEvent("pb-aaa", "bug", "2024-01-01T00:03:00Z"),
+		NewLabelAddEvent("pb-aaa", "stale", "2024-01-01T00:04:00Z"),
+		NewLabelRemoveEvent("pb-aaa", "stale", "2024-01-01T00:05:00Z"),
+		NewRenameEvent("pb-aaa", "pb-bbb", "2024-01-01T00:06:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	// Labels come back sorted under the renamed ID, and old IDs still resolve.
+	issue, _, err := GetIssue(root, "pb-aaa")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.ID != "pb-bbb" {
+		t.Fatalf("expected renamed id pb-bbb, got %s", issue.ID)
+	}
+	if want := []string{"bug", "frontend"}; !reflect.DeepEqual(issue.Labels, want) {
+		t.Fatalf("expected labels %v, got %v", want, issue.Labels)
+	}
+	if issue.UpdatedAt != "2024-01-01T00:06:00Z" {
+		t.Fatalf("expected updated_at from the last event, got %s", issue.UpdatedAt)
+	}
+}
+
+// TestLabelAddMissingIssue verifies label events require an existing issue.
+func TestLabelAddMissingIssue(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	if err := AppendEvent(root, NewLabelAddEvent("pb-missing", "bug", "2024-01-01T00:00:00Z")); err != nil {
+		t.Fatalf("append label: %v", err)
+	}
+	if err := RebuildCache(root); err == nil {
+		t.Fatalf("expected rebuild to fail for a label on a missing issue")
+	}
+}
+
+// TestNormalizeLabel verifies label normalization and validation.
+func TestNormalizeLabel(t *testing.T) {
+	label, err := NormalizeLabel("  FrontEnd ")
+	if err != nil {
+		t.Fatalf("normalize label: %v", err)
+	}
+	if label != "frontend" {
+		t.Fatalf("expected frontend, got %q", label)
+	}
+	for _, input := range []string{"", "  ", "a,b", "two words"} {
+		if _, err := NormalizeLabel(input); err == nil {
+			t.Fatalf("expected error for label %q", input)
+		}
+	}
+}
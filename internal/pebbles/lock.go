@@ -0,0 +1,120 @@
+package pebbles
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	lockTimeout      = 30 * time.Second
+	lockPollInterval = 50 * time.Millisecond
+)
+
+// heldLock tracks an acquired project lock so nested callers can share it.
+type heldLock struct {
+	file  *os.File
+	depth int
+}
+
+var (
+	heldLocksMu sync.Mutex
+	heldLocks   = make(map[string]*heldLock)
+)
+
+// WithLock runs fn while holding the project's advisory lock.
+func WithLock(root string, fn func() error) error {
+	unlock, err := LockProject(root)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// LockProject acquires the advisory lock guarding the event log and cache.
+// The lock is re-entrant within a process; call the returned func to release it.
+func LockProject(root string) (func(), error) {
+	path := LockPath(root)
+	heldLocksMu.Lock()
+	defer heldLocksMu.Unlock()
+	// Nested acquisitions only bump the depth of the existing lock.
+	if held, ok := heldLocks[path]; ok {
+		held.depth++
+		return func() { releaseLock(path) }, nil
+	}
+	file, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		// A read-only project without a lock file cannot be written, so there
+		// is nothing to guard.
+		return func() {}, nil
+	}
+	if err := acquireFileLock(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	heldLocks[path] = &heldLock{file: file, depth: 1}
+	return func() { releaseLock(path) }, nil
+}
+
+// openLockFile opens the lock file, creating it when the project is writable.
+// A read-only project falls back to an existing lock file, or to none.
+func openLockFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, fs.ErrPermission) && !errors.Is(err, syscall.EROFS) {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+	file, err = os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+	return file, nil
+}
+
+// acquireFileLock polls for an exclusive flock until the timeout elapses.
+func acquireFileLock(file *os.File) error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			return fmt.Errorf("lock pebbles: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for pebbles lock: %s", file.Name())
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// releaseLock drops one level of a held lock and unlocks at depth zero.
+func releaseLock(path string) {
+	heldLocksMu.Lock()
+	defer heldLocksMu.Unlock()
+	held, ok := heldLocks[path]
+	if !ok {
+		return
+	}
+	held.depth--
+	if held.depth > 0 {
+		return
+	}
+	delete(heldLocks, path)
+	_ = syscall.Flock(int(held.file.Fd()), syscall.LOCK_UN)
+	_ = held.file.Close()
+}
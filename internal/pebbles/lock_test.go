@@ -0,0 +1,168 @@
+package pebbles
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestWithLockIsReentrant(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	// Appending and rebuilding while already holding the lock must not deadlock.
+	err := WithLock(root, func() error {
+		if err := AppendEvent(root, NewCreateEvent("pb-1", "Locked", "", "task", "2024-01-01T00:00:00Z", 2)); err != nil {
+			return err
+		}
+		return RebuildCache(root)
+	})
+	if err != nil {
+		t.Fatalf("with lock: %v", err)
+	}
+	if exists, err := IssueExists(root, "pb-1"); err != nil || !exists {
+		t.Fatalf("expected pb-1 to exist, got %v (err=%v)", exists, err)
+	}
+}
+
+func TestLockProjectExcludesOtherHolders(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	unlock, err := LockProject(root)
+	if err != nil {
+		t.Fatalf("lock project: %v", err)
+	}
+	// A separate file description stands in for another pb process.
+	other, err := os.OpenFile(LockPath(root), os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatalf("open lock file: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+	err = syscall.Flock(int(other.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if !errors.Is(err, syscall.EWOULDBLOCK) {
+		t.Fatalf("expected lock to be held, got %v", err)
+	}
+	unlock()
+	if err := syscall.Flock(int(other.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		t.Fatalf("expected lock to be released: %v", err)
+	}
+}
+
+func TestReadsLeaveLockAndGitignoreAlone(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	// Simulate a project initialized before the lock file was ignored.
+	path := filepath.Join(PebblesDir(root), ".gitignore")
+	if err := os.WriteFile(path, []byte("pebbles.db\n"), 0600); err != nil {
+		t.Fatalf("write .gitignore: %v", err)
+	}
+	if err := os.Remove(LockPath(root)); err != nil && !os.IsNotExist(err) {
+		t.Fatalf("remove lock file: %v", err)
+	}
+	if _, err := ListIssues(root); err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if _, err := os.Stat(LockPath(root)); !os.IsNotExist(err) {
+		t.Fatalf("expected a read with a fresh cache not to create the lock file, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "pebbles.db\n" {
+		t.Fatalf("expected a read to leave .gitignore alone, got %q (%v)", data, err)
+	}
+
+	if err := AppendEvent(root, NewCreateEvent("pb-1", "First", "", "task", "2024-01-01T00:00:00Z", 2)); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read .gitignore: %v", err)
+	}
+	if string(data) != "pebbles.db\npebbles.lock\nviews.local.json\n" {
+		t.Fatalf("expected missing entries appended on write, got %q", data)
+	}
+}
+
+func TestAppendEventsClocksPastUnreplayableLines(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	if err := AppendEvent(root, NewCreateEvent("pb-1", "First", "", "task", "2024-01-01T00:00:00Z", 2)); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	// A clocked line past the checkpoint, then a leftover conflict marker that
+	// makes the log fail to replay.
+	file, err := os.OpenFile(EventsPath(root), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("open events log: %v", err)
+	}
+	tail := `{"version":1,"clock":7,"type":"create","timestamp":"2024-01-01T00:00:01Z","issue_id":"pb-2","payload":{"title":"Second","type":"task","priority":"2"}}` + "\n<<<<<<< HEAD\n"
+	if _, err := file.WriteString(tail); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	_ = file.Close()
+	if err := RebuildCache(root); err == nil {
+		t.Fatalf("expected the conflict marker to break replay")
+	}
+
+	if err := AppendEvent(root, NewCreateEvent("pb-3", "Third", "", "task", "2024-01-01T00:00:02Z", 2)); err != nil {
+		t.Fatalf("append past an unreplayable log: %v", err)
+	}
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		t.Fatalf("read events log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	last, err := decodeEvent([]byte(lines[len(lines)-1]))
+	if err != nil {
+		t.Fatalf("decode appended event: %v", err)
+	}
+	if last.IssueID != "pb-3" || last.Clock != 8 {
+		t.Fatalf("expected pb-3 at clock 8, got %s at %d", last.IssueID, last.Clock)
+	}
+}
+
+func TestAppendEventsStartsOnNewLine(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	// Simulate a log whose last line lost its newline, e.g. after a hand edit.
+	create := `{"type":"create","timestamp":"2024-01-01T00:00:00Z","issue_id":"pb-1","payload":{"title":"First","type":"task","priority":"2"}}`
+	if err := os.WriteFile(EventsPath(root), []byte(create), 0600); err != nil {
+		t.Fatalf("write events log: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-2", "Second", "", "task", "2024-01-01T00:00:01Z", 2),
+		NewDepAddEvent("pb-2", "pb-1", DepTypeBlocks, "2024-01-01T00:00:02Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	data, err := os.ReadFile(EventsPath(root))
+	if err != nil {
+		t.Fatalf("read events log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %d", len(lines))
+	}
+	loaded, err := LoadEvents(root)
+	if err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	if len(loaded) != 3 || loaded[2].Type != EventTypeDepAdd {
+		t.Fatalf("unexpected events: %+v", loaded)
+	}
+}
@@ -1,17 +1,16 @@
 package pebbles
 
 import (
-	"bufio"
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"os"
-	"strings"
 )
 
-// EventLogEntry pairs an event with its line number in the log.
+// EventLogEntry pairs an event with its line number and raw text in the log.
 type EventLogEntry struct {
 	Line  int
 	Event Event
+	Raw   string
 }
 
 // LoadEventLog reads the event log and returns entries with line numbers.
@@ -19,32 +18,66 @@ func LoadEventLog(root string) ([]EventLogEntry, error) {
 	return readEventLog(EventsPath(root))
 }
 
+// rawLogLine is a non-blank event log line with its 1-based line number.
+type rawLogLine struct {
+	Number int
+	Text   []byte
+}
+
 // readEventLog reads a JSONL log file and records line numbers for each event.
 func readEventLog(path string) ([]EventLogEntry, error) {
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("open events log: %w", err)
 	}
-	defer func() { _ = file.Close() }()
-	// Scan the log line by line to capture line numbers.
-	scanner := bufio.NewScanner(file)
+	// Decode each line into an Event, reporting the line on errors.
 	var entries []EventLogEntry
-	lineNumber := 0
-	for scanner.Scan() {
+	for _, line := range splitLogLines(data) {
+		event, err := decodeLogLine(line)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, EventLogEntry{Line: line.Number, Event: event, Raw: string(line.Text)})
+	}
+	return entries, nil
+}
+
+// decodeLogLine decodes a log line, flagging git conflict markers explicitly.
+func decodeLogLine(line rawLogLine) (Event, error) {
+	if isConflictMarker(line.Text) {
+		return Event{}, &ConflictMarkerError{Line: line.Number}
+	}
+	event, err := decodeEvent(line.Text)
+	if err != nil {
+		return Event{}, fmt.Errorf("parse event line %d: %w", line.Number, err)
+	}
+	return event, nil
+}
+
+// splitLogLines splits log data into trimmed lines, skipping blank ones.
+func splitLogLines(data []byte) []rawLogLine {
+	return splitLogLinesFrom(data, 1)
+}
+
+// splitLogLinesFrom splits log data that starts at line firstLine of the
+// file, so line numbers in diagnostics match the file.
+func splitLogLinesFrom(data []byte, firstLine int) []rawLogLine {
+	var lines []rawLogLine
+	lineNumber := firstLine - 1
+	for len(data) > 0 {
 		lineNumber++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+		line := data
+		if index := bytes.IndexByte(data, '\n'); index >= 0 {
+			line = data[:index]
+			data = data[index+1:]
+		} else {
+			data = nil
 		}
-		// Decode JSON into an Event, reporting the line on errors.
-		var event Event
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			return nil, fmt.Errorf("parse event line %d: %w", lineNumber, err)
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
 		}
-		entries = append(entries, EventLogEntry{Line: lineNumber, Event: event})
+		lines = append(lines, rawLogLine{Number: lineNumber, Text: line})
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scan events log: %w", err)
-	}
-	return entries, nil
+	return lines
 }
@@ -7,6 +7,7 @@ import (
 
 func TestMain(m *testing.M) {
 	_ = os.Unsetenv("PEBBLES_DIR")
+	_ = os.Unsetenv(ActorEnvVar)
 	os.Exit(m.Run())
 }
 
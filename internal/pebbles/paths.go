@@ -25,7 +25,17 @@ func ConfigPath(root string) string {
 	return filepath.Join(PebblesDir(root), "config.json")
 }
 
+// ViewsLocalPath returns the path of the untracked personal views file.
+func ViewsLocalPath(root string) string {
+	return filepath.Join(PebblesDir(root), "views.local.json")
+}
+
 // DBPath returns the SQLite cache path for a project root.
 func DBPath(root string) string {
 	return filepath.Join(PebblesDir(root), "pebbles.db")
 }
+
+// LockPath returns the advisory lock file path for a project root.
+func LockPath(root string) string {
+	return filepath.Join(PebblesDir(root), "pebbles.lock")
+}
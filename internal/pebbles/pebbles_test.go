@@ -3,6 +3,7 @@ package pebbles
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -248,6 +249,46 @@ func TestRenameUpdatesDeps(t *testing.T) {
 	}
 }
 
+// TestRenameMovesLabelsFieldsAndComments ensures rows keyed by issue ID follow a rename.
+func TestRenameMovesLabelsFieldsAndComments(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	comment := NewCommentEvent("pb-old", "Before the rename", "2024-01-04T00:00:03Z")
+	events := []Event{
+		NewCreateEvent("pb-old", "First", "", "task", "2024-01-04T00:00:00Z", 2),
+		NewLabelAddEvent("pb-old", "backend", "2024-01-04T00:00:01Z"),
+		NewFieldSetEvent("pb-old", "customer", "Acme", "2024-01-04T00:00:02Z"),
+		comment,
+		NewRenameEvent("pb-old", "pb-new", "2024-01-04T01:00:00Z"),
+		NewCommentEditEvent("pb-new", CommentID(comment), "After the rename", "2024-01-04T02:00:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-new")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0] != "backend" {
+		t.Fatalf("expected the label to follow the rename, got %v", issue.Labels)
+	}
+	if issue.Fields["customer"] != "Acme" {
+		t.Fatalf("expected the field to follow the rename, got %v", issue.Fields)
+	}
+	comments, err := ListIssueComments(root, "pb-new")
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if len(comments) != 1 || comments[0].IssueID != "pb-new" || comments[0].Body != "After the rename" {
+		t.Fatalf("expected the comment to follow the rename, got %+v", comments)
+	}
+}
+
 // TestReadyList verifies dependency-based ready filtering.
 func TestReadyList(t *testing.T) {
 	root := t.TempDir()
@@ -483,3 +524,179 @@ func fileExists(path string) (bool, error) {
 	}
 	return !info.IsDir(), nil
 }
+
+// TestAssignFollowsRenames verifies assign events resolve renamed IDs.
+func TestAssignFollowsRenames(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-old", "Owned", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewRenameEvent("pb-old", "pb-new", "2024-01-01T00:01:00Z"),
+		NewAssignEvent("pb-old", "dev@example.com", "2024-01-01T00:02:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-new")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.Assignee != "dev@example.com" {
+		t.Fatalf("expected assignee dev@example.com, got %q", issue.Assignee)
+	}
+	// An empty assignee clears the field.
+	if err := AppendEvent(root, NewAssignEvent("pb-new", "", "2024-01-01T00:03:00Z")); err != nil {
+		t.Fatalf("append unassign: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache after unassign: %v", err)
+	}
+	issue, _, err = GetIssue(root, "pb-new")
+	if err != nil {
+		t.Fatalf("get issue after unassign: %v", err)
+	}
+	if issue.Assignee != "" {
+		t.Fatalf("expected no assignee, got %q", issue.Assignee)
+	}
+}
+
+// TestCloseResolutionAndReopen verifies close resolutions persist until reopen.
+func TestCloseResolutionAndReopen(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-res", "Resolved", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewResolvedCloseEvent("pb-res", ResolutionWontFix, "Out of scope", "2024-01-01T00:01:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-res")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.Resolution != ResolutionWontFix || issue.CloseReason != "Out of scope" {
+		t.Fatalf("expected wontfix/Out of scope, got %q/%q", issue.Resolution, issue.CloseReason)
+	}
+	// Reopening clears the resolution and reason.
+	if err := AppendEvent(root, NewStatusEvent("pb-res", StatusOpen, "2024-01-01T00:02:00Z")); err != nil {
+		t.Fatalf("append reopen: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache after reopen: %v", err)
+	}
+	issue, _, err = GetIssue(root, "pb-res")
+	if err != nil {
+		t.Fatalf("get issue after reopen: %v", err)
+	}
+	if issue.Resolution != "" || issue.CloseReason != "" {
+		t.Fatalf("expected resolution cleared, got %q/%q", issue.Resolution, issue.CloseReason)
+	}
+}
+
+// TestParseResolution verifies resolution aliases and validation.
+func TestParseResolution(t *testing.T) {
+	for input, want := range map[string]string{"Done": ResolutionDone, "won't-fix": ResolutionWontFix, "wont_fix": ResolutionWontFix, "": ""} {
+		got, err := ParseResolution(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseResolution(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseResolution("fixed-later"); err == nil {
+		t.Fatalf("expected an error for an unknown resolution")
+	}
+}
+
+// TestDeleteHidesIssueAndRestoresDeps verifies delete/undelete round trips.
+func TestDeleteHidesIssueAndRestoresDeps(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-a", "Blocked", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewCreateEvent("pb-b", "Blocker", "", "task", "2024-01-01T00:00:01Z", 2),
+		NewDepAddEvent("pb-a", "pb-b", DepTypeBlocks, "2024-01-01T00:01:00Z"),
+		NewCommentEvent("pb-b", "Context worth keeping", "2024-01-01T00:02:00Z"),
+		NewDeleteEvent("pb-b", "Mistake", "2024-01-01T00:03:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	// The deleted issue is hidden and no longer blocks its dependent.
+	if _, _, err := GetIssue(root, "pb-b"); err == nil || !strings.Contains(err.Error(), "deleted") {
+		t.Fatalf("expected deleted error, got %v", err)
+	}
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("ready issues: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != "pb-a" {
+		t.Fatalf("expected only pb-a ready, got %+v", ready)
+	}
+	items, err := ListIssueHierarchy(root)
+	if err != nil {
+		t.Fatalf("list hierarchy: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected deleted issue hidden from list, got %d items", len(items))
+	}
+	deleted, _, err := GetIssueIncludingDeleted(root, "pb-b")
+	if err != nil {
+		t.Fatalf("get deleted issue: %v", err)
+	}
+	if deleted.DeletedAt != "2024-01-01T00:03:00Z" || deleted.DeleteReason != "Mistake" {
+		t.Fatalf("unexpected delete fields: %q %q", deleted.DeletedAt, deleted.DeleteReason)
+	}
+	comments, err := ListIssueComments(root, "pb-b")
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected comments kept, got %d", len(comments))
+	}
+	// With both ends deleted, undeleting one end keeps the edge detached.
+	more := []Event{
+		NewDeleteEvent("pb-a", "", "2024-01-01T00:04:00Z"),
+		NewUndeleteEvent("pb-b", "2024-01-01T00:05:00Z"),
+	}
+	if err := AppendEvents(root, more); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	ready, err = ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("ready issues: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != "pb-b" {
+		t.Fatalf("expected only pb-b ready, got %+v", ready)
+	}
+	if err := AppendEvent(root, NewUndeleteEvent("pb-a", "2024-01-01T00:06:00Z")); err != nil {
+		t.Fatalf("append undelete: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, deps, err := GetIssue(root, "pb-a")
+	if err != nil {
+		t.Fatalf("get restored issue: %v", err)
+	}
+	if issue.DeletedAt != "" || len(deps) != 1 || deps[0] != "pb-b" {
+		t.Fatalf("expected pb-a restored with its blocker, got %+v deps %v", issue, deps)
+	}
+}
@@ -59,15 +59,37 @@ func ensureEventsFile(root string) error {
 	return file.Close()
 }
 
-// ensureGitignore writes a .pebbles/.gitignore if one does not exist.
+// gitignoreEntries lists the local-only files under .pebbles.
+var gitignoreEntries = []string{"pebbles.db", "pebbles.lock", "views.local.json"}
+
+// ensureGitignore writes a .pebbles/.gitignore, adding any missing entries.
 func ensureGitignore(root string) error {
 	path := filepath.Join(PebblesDir(root), ".gitignore")
-	if _, err := os.Stat(path); err == nil {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read .pebbles/.gitignore: %w", err)
+	}
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	changed := false
+	for _, entry := range gitignoreEntries {
+		if present[entry] {
+			continue
+		}
+		content += entry + "\n"
+		changed = true
+	}
+	if !changed {
 		return nil
 	}
-	content := []byte("pebbles.db\n")
-	if err := os.WriteFile(path, content, 0600); err != nil {
-		return fmt.Errorf("create .pebbles/.gitignore: %w", err)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("write .pebbles/.gitignore: %w", err)
 	}
 	return nil
 }
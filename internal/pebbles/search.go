@@ -0,0 +1,145 @@
+package pebbles
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// SnippetMatchStart and SnippetMatchEnd surround matched terms in search
+	// snippets so callers can highlight them however they like.
+	SnippetMatchStart = "\x02"
+	SnippetMatchEnd   = "\x03"
+)
+
+// SearchResult is one issue matching a search, best matches first.
+type SearchResult struct {
+	Issue Issue
+	// Snippet is a short excerpt around the best match, with matched terms
+	// wrapped in SnippetMatchStart and SnippetMatchEnd.
+	Snippet string
+}
+
+// searchIndexInsert fills search index rows for live issues; callers may
+// append further conditions.
+const searchIndexInsert = `
+	INSERT INTO issue_search (issue_id, title, description, comments)
+	SELECT i.id, i.title, i.description,
+		(SELECT COALESCE(group_concat(c.body, char(10)), '') FROM comments c
+		WHERE c.issue_id = i.id AND c.deleted_at = '')
+	FROM issues i
+	WHERE i.deleted_at = ''`
+
+// rebuildSearchIndex refills the full-text index from the live issues and
+// their comments. A full replay runs it after applying the log, so the index
+// always matches the cache it was built from.
+func rebuildSearchIndex(db sqlExecutor) error {
+	if _, err := db.Exec("DELETE FROM issue_search"); err != nil {
+		return fmt.Errorf("clear search index: %w", err)
+	}
+	if _, err := db.Exec(searchIndexInsert); err != nil {
+		return fmt.Errorf("fill search index: %w", err)
+	}
+	return nil
+}
+
+// reindexIssues replaces the search index rows of the given issue IDs,
+// dropping rows for IDs that no longer name a live issue.
+func reindexIssues(db sqlExecutor, ids map[string]bool) error {
+	for id := range ids {
+		if _, err := db.Exec("DELETE FROM issue_search WHERE issue_id = ?", id); err != nil {
+			return fmt.Errorf("clear search index for %s: %w", id, err)
+		}
+		if _, err := db.Exec(searchIndexInsert+" AND i.id = ?", id); err != nil {
+			return fmt.Errorf("fill search index for %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// collectEventIssueIDs adds the current ID of each event's issue to ids.
+// Called before and after applying a batch, it yields both the IDs the index
+// was keyed by and the IDs the batch left behind, covering renames.
+func collectEventIssueIDs(db sqlExecutor, events []Event, ids map[string]bool) error {
+	for _, event := range events {
+		if strings.TrimSpace(event.IssueID) == "" {
+			continue
+		}
+		id, err := resolveIssueID(db, event.IssueID)
+		if err != nil {
+			return err
+		}
+		ids[id] = true
+	}
+	return nil
+}
+
+// SearchIssues runs a full-text query over issue titles, descriptions, and
+// comments, ranking title matches above description and comment matches.
+// Every word must match; a word ending in * matches as a prefix.
+func SearchIssues(root, query string) ([]SearchResult, error) {
+	match := searchMatchExpression(query)
+	if match == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	if err := EnsureCache(root); err != nil {
+		return nil, err
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	// bm25 weights: issue_id is unindexed, then title, description, comments.
+	rows, err := db.Query(`
+		SELECT `+issueColumns("i")+`,
+			snippet(issue_search, -1, ?, ?, '…', 12)
+		FROM issue_search
+		JOIN issues i ON i.id = issue_search.issue_id
+		WHERE issue_search MATCH ?
+		ORDER BY bm25(issue_search, 0.0, 10.0, 4.0, 1.0), i.id
+	`, SnippetMatchStart, SnippetMatchEnd, match)
+	if err != nil {
+		return nil, fmt.Errorf("search issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var labels, fields string
+		targets := append(issueScanTargets(&result.Issue, &labels, &fields), &result.Snippet)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		result.Issue.Labels = splitLabels(labels)
+		if result.Issue.Fields, err = decodeFields(fields); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search rows: %w", err)
+	}
+	return results, nil
+}
+
+// searchMatchExpression turns user input into an FTS5 query. Each word is
+// quoted so punctuation such as the hyphen in "pb-12" is not read as query
+// syntax; a trailing * keeps prefix matching.
+func searchMatchExpression(query string) string {
+	words := strings.Fields(query)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
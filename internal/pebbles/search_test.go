@@ -0,0 +1,114 @@
+package pebbles
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSearchIssuesRanksAndTracksReplay verifies ranking across columns and
+// that appended comments, deleted comments, and deleted issues update the index.
+func TestSearchIssuesRanksAndTracksReplay(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	comment := NewCommentEvent("pb-docs", "The login redirect belongs here", "2024-01-01T00:00:03Z")
+	events := []Event{
+		NewCreateEvent("pb-docs", "Document API", "Describe the auth flow", "task", "2024-01-01T00:00:00Z", 2),
+		NewCreateEvent("pb-login", "Login page crashes", "", "bug", "2024-01-01T00:00:01Z", 1),
+		NewCreateEvent("pb-gone", "Login cleanup", "", "task", "2024-01-01T00:00:02Z", 2),
+		comment,
+		NewDeleteEvent("pb-gone", "", "2024-01-01T00:00:04Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	results, err := SearchIssues(root, "login")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 || results[0].Issue.ID != "pb-login" || results[1].Issue.ID != "pb-docs" {
+		t.Fatalf("expected the title match before the comment match, got %v", results)
+	}
+	if !strings.Contains(results[1].Snippet, SnippetMatchStart+"login"+SnippetMatchEnd) {
+		t.Fatalf("expected a highlighted comment snippet, got %q", results[1].Snippet)
+	}
+	if results, err = SearchIssues(root, "crash"); err != nil || len(results) != 1 {
+		t.Fatalf("expected stemmed match for crash, got %v (%v)", results, err)
+	}
+	if results, err = SearchIssues(root, "redir*"); err != nil || len(results) != 1 {
+		t.Fatalf("expected prefix match for redir*, got %v (%v)", results, err)
+	}
+
+	// An incremental replay refreshes the index.
+	if err := AppendEvent(root, NewCommentDeleteEvent("pb-docs", CommentID(comment), "2024-01-01T00:00:05Z")); err != nil {
+		t.Fatalf("append comment delete: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	results, err = SearchIssues(root, "login")
+	if err != nil {
+		t.Fatalf("search after delete: %v", err)
+	}
+	if len(results) != 1 || results[0].Issue.ID != "pb-login" {
+		t.Fatalf("expected deleted comment to leave the index, got %v", results)
+	}
+	if _, err := SearchIssues(root, "  "); err == nil {
+		t.Fatalf("expected an empty query to fail")
+	}
+}
+
+// TestSearchIndexTailReplayFollowsRenames verifies a tail replay re-indexes
+// renamed and edited issues without leaving rows under old IDs.
+func TestSearchIndexTailReplayFollowsRenames(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-old", "Flaky upload", "", "bug", "2024-01-01T00:00:00Z", 1),
+		NewCreateEvent("pb-other", "Upload docs", "", "task", "2024-01-01T00:00:01Z", 2),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	tail := []Event{
+		NewRenameEvent("pb-old", "pb-new", "2024-01-01T00:00:02Z"),
+		NewTitleUpdatedEvent("pb-new", "Flaky download", "2024-01-01T00:00:03Z"),
+	}
+	if err := AppendEvents(root, tail); err != nil {
+		t.Fatalf("append tail: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	results, err := SearchIssues(root, "flaky")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Issue.ID != "pb-new" || results[0].Issue.Title != "Flaky download" {
+		t.Fatalf("expected the renamed issue once, got %v", results)
+	}
+	if results, err = SearchIssues(root, "upload"); err != nil || len(results) != 1 || results[0].Issue.ID != "pb-other" {
+		t.Fatalf("expected only the untouched issue for upload, got %v (%v)", results, err)
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	var rows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM issue_search").Scan(&rows); err != nil {
+		t.Fatalf("count index rows: %v", err)
+	}
+	if rows != 2 {
+		t.Fatalf("expected 2 index rows, got %d", rows)
+	}
+}
@@ -0,0 +1,157 @@
+package pebbles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OpenSnapshot replays the event log as it stood at asOf into a temporary
+// project and returns that project's root, plus a function that removes it.
+// asOf is an RFC3339 timestamp, a YYYY-MM-DD date (through the end of that
+// local day), today, yesterday, a past offset such as -7d, or a git ref whose
+// committed .pebbles/events.jsonl is replayed in full. The live cache is not
+// touched, and the snapshot uses the live config.
+func OpenSnapshot(root, asOf string, now time.Time) (string, func(), error) {
+	data, err := snapshotLog(root, asOf, now)
+	if err != nil {
+		return "", nil, err
+	}
+	config, err := os.ReadFile(ConfigPath(root))
+	if err != nil {
+		return "", nil, fmt.Errorf("read config: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "pebbles-as-of-")
+	if err != nil {
+		return "", nil, fmt.Errorf("create snapshot dir: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	if err := writeSnapshot(filepath.Join(dir, ".pebbles"), config, data); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return dir, cleanup, nil
+}
+
+// writeSnapshot writes the config and log into a snapshot's .pebbles
+// directory and replays the log into its cache.
+func writeSnapshot(dir string, config, data []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create snapshot dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), config, 0600); err != nil {
+		return fmt.Errorf("write snapshot config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "events.jsonl"), data, 0600); err != nil {
+		return fmt.Errorf("write snapshot log: %w", err)
+	}
+	db, err := openDB(filepath.Join(dir, "pebbles.db"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	return replayFullLog(db, data)
+}
+
+// snapshotLog returns the log data a snapshot replays: the live log cut off
+// at a point in time, or the log committed at a git ref.
+func snapshotLog(root, asOf string, now time.Time) ([]byte, error) {
+	asOf = strings.TrimSpace(asOf)
+	if asOf == "" {
+		return nil, fmt.Errorf("as-of requires a timestamp, date, or git ref")
+	}
+	if cutoff, ok := parseAsOfTime(asOf, now); ok {
+		data, err := os.ReadFile(EventsPath(root))
+		if err != nil {
+			return nil, fmt.Errorf("read events log: %w", err)
+		}
+		return eventsUpTo(data, cutoff)
+	}
+	// A leading dash would make git read the ref as an option.
+	if strings.HasPrefix(asOf, "-") {
+		return nil, fmt.Errorf("invalid as-of %q (use -Nm, -Nh, -Nd, or -Nw)", asOf)
+	}
+	output, err := exec.Command("git", "-C", root, "show", asOf+":./.pebbles/events.jsonl").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			message := strings.TrimSpace(strings.SplitN(string(exitErr.Stderr), "\n", 2)[0])
+			return nil, fmt.Errorf("as-of %q is not a timestamp, date, or git ref with an event log: %s", asOf, message)
+		}
+		return nil, fmt.Errorf("read events log at %s: %w", asOf, err)
+	}
+	return output, nil
+}
+
+// parseAsOfTime resolves the time forms of --as-of to the last instant they
+// include. A date covers its whole local day.
+func parseAsOfTime(value string, now time.Time) (time.Time, bool) {
+	if instant, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return instant, true
+	}
+	if strings.HasPrefix(value, "-") {
+		if offset, err := parseWhereOffset(strings.ToLower(value)); err == nil {
+			return now.Add(offset), true
+		}
+		return time.Time{}, false
+	}
+	var day time.Time
+	switch lower := strings.ToLower(value); lower {
+	case "today":
+		day = localDate(now)
+	case "yesterday":
+		day = localDate(now).AddDate(0, 0, -1)
+	default:
+		parsed, err := time.ParseInLocation(dateLayout, value, now.Location())
+		if err != nil {
+			return time.Time{}, false
+		}
+		day = parsed
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+}
+
+// eventsUpTo keeps the log lines replay would apply up to cutoff. Replay
+// follows Lamport clocks, not timestamps, so it finds the last event in replay
+// order stamped at or before cutoff and keeps that event and everything replay
+// applies before it. An event from a host whose clock ran behind therefore
+// still sees its causal predecessors, even ones stamped after cutoff. Lines
+// whose timestamp does not parse count as stamped before cutoff. Kept lines
+// stay in file order.
+func eventsUpTo(data []byte, cutoff time.Time) ([]byte, error) {
+	lines := splitLogLines(data)
+	events := make([]Event, len(lines))
+	for i, line := range lines {
+		event, err := decodeLogLine(line)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+	order := replayOrder(events, 0)
+	last := -1
+	for position, index := range order {
+		timestamp, err := time.Parse(time.RFC3339Nano, events[index].Timestamp)
+		if err != nil || !timestamp.After(cutoff) {
+			last = position
+		}
+	}
+	keep := make([]bool, len(lines))
+	for _, index := range order[:last+1] {
+		keep[index] = true
+	}
+	var buffer bytes.Buffer
+	for i, line := range lines {
+		if !keep[i] {
+			continue
+		}
+		buffer.Write(line.Text)
+		buffer.WriteByte('\n')
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package pebbles
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenSnapshotReplaysEventsUpToTime(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-early", "Early", "", "task", "2024-01-01T09:00:00Z", 2),
+		NewCreateEvent("pb-late", "Late", "", "task", "2024-01-03T09:00:00Z", 2),
+		NewCloseEvent("pb-early", "2024-01-03T10:00:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+
+	now := time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC)
+	for _, asOf := range []string{"2024-01-02", "2024-01-02T00:00:00Z", "-2d"} {
+		snapshot, cleanup, err := OpenSnapshot(root, asOf, now)
+		if err != nil {
+			t.Fatalf("%s: open snapshot: %v", asOf, err)
+		}
+		issues, err := ListIssues(snapshot)
+		cleanup()
+		if err != nil {
+			t.Fatalf("%s: list snapshot: %v", asOf, err)
+		}
+		if len(issues) != 1 || issues[0].ID != "pb-early" || issues[0].Status != StatusOpen {
+			t.Fatalf("%s: expected only the still-open early issue, got %+v", asOf, issues)
+		}
+	}
+
+	// The live cache still sees every event.
+	issue, _, err := GetIssue(root, "pb-early")
+	if err != nil || issue.Status != StatusClosed {
+		t.Fatalf("expected the live cache untouched, got %+v (%v)", issue, err)
+	}
+	if _, _, err := OpenSnapshot(root, "--output=x", now); err == nil {
+		t.Fatalf("expected an option-like as-of rejected")
+	}
+}
+
+func TestOpenSnapshotKeepsCausalPredecessorsOfSkewedEvents(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	// The comment is stamped 12:00 by one host; the edit that follows it (a
+	// higher clock) is stamped 11:00 by a host whose clock runs behind.
+	comment := NewCommentEvent("pb-1", "Draft", "2024-01-01T12:00:00Z")
+	events := []Event{
+		NewCreateEvent("pb-1", "Skewed", "", "task", "2024-01-01T10:00:00Z", 2),
+		comment,
+		NewCommentEditEvent("pb-1", CommentID(comment), "Final", "2024-01-01T11:00:00Z"),
+		NewCloseEvent("pb-1", "2024-01-01T13:00:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	snapshot, cleanup, err := OpenSnapshot(root, "2024-01-01T11:30:00Z", now)
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer cleanup()
+	comments, err := ListIssueComments(snapshot, "pb-1")
+	if err != nil {
+		t.Fatalf("list snapshot comments: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Body != "Final" {
+		t.Fatalf("expected the edit and the comment it targets, got %+v", comments)
+	}
+	issue, _, err := GetIssue(snapshot, "pb-1")
+	if err != nil || issue.Status != StatusOpen {
+		t.Fatalf("expected the later close left out, got %+v (%v)", issue, err)
+	}
+}
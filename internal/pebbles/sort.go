@@ -0,0 +1,139 @@
+package pebbles
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is one field of a --sort spec; Desc reverses its order.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// sortFields compares two issues on one field. Unset values (no due date,
+// no assignee, never closed) sort last in either direction, so they return
+// a flag instead of an ordering.
+var sortFields = map[string]func(left, right Issue) (cmp int, leftUnset, rightUnset bool){
+	"id":       func(left, right Issue) (int, bool, bool) { return strings.Compare(left.ID, right.ID), false, false },
+	"title":    compareText(func(issue Issue) string { return issue.Title }),
+	"type":     compareText(func(issue Issue) string { return issue.IssueType }),
+	"status":   compareText(func(issue Issue) string { return issue.Status }),
+	"assignee": compareText(func(issue Issue) string { return issue.Assignee }),
+	"priority": compareInt(func(issue Issue) int { return issue.Priority }),
+	"created":  compareTime(func(issue Issue) string { return issue.CreatedAt }),
+	"updated":  compareTime(func(issue Issue) string { return issue.UpdatedAt }),
+	"closed":   compareTime(func(issue Issue) string { return issue.ClosedAt }),
+	"due":      compareTime(func(issue Issue) string { return issue.DueAt }),
+	"deferred": compareTime(func(issue Issue) string { return issue.DeferredUntil }),
+	"logged":   compareInt(func(issue Issue) int { return issue.LoggedMinutes }),
+}
+
+// compareText builds a case-insensitive comparison of a string field.
+func compareText(value func(Issue) string) func(left, right Issue) (int, bool, bool) {
+	return func(left, right Issue) (int, bool, bool) {
+		leftValue, rightValue := strings.ToLower(value(left)), strings.ToLower(value(right))
+		return strings.Compare(leftValue, rightValue), leftValue == "", rightValue == ""
+	}
+}
+
+// compareTime builds a comparison of a timestamp or date field by instant.
+func compareTime(value func(Issue) string) func(left, right Issue) (int, bool, bool) {
+	return func(left, right Issue) (int, bool, bool) {
+		leftValue, rightValue := value(left), value(right)
+		return compareInstants(leftValue, rightValue), leftValue == "", rightValue == ""
+	}
+}
+
+// compareInstants orders two RFC3339 timestamps or YYYY-MM-DD dates by the
+// instant they name, so fraction lengths and UTC offsets don't skew the order
+// the way a string comparison would. Values that don't parse sort after those
+// that do, and among themselves as text.
+func compareInstants(left, right string) int {
+	leftTime, leftErr := parseInstant(left)
+	rightTime, rightErr := parseInstant(right)
+	switch {
+	case leftErr == nil && rightErr == nil:
+		return leftTime.Compare(rightTime)
+	case leftErr == nil:
+		return -1
+	case rightErr == nil:
+		return 1
+	}
+	return strings.Compare(left, right)
+}
+
+// parseInstant parses an RFC3339 timestamp, or a date as midnight UTC.
+func parseInstant(value string) (time.Time, error) {
+	if instant, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return instant, nil
+	}
+	return time.Parse(dateLayout, value)
+}
+
+// compareInt builds a comparison of a numeric field, which is never unset.
+func compareInt(value func(Issue) int) func(left, right Issue) (int, bool, bool) {
+	return func(left, right Issue) (int, bool, bool) {
+		return value(left) - value(right), false, false
+	}
+}
+
+// ParseSortKeys parses a comma-separated sort spec such as
+// "priority,-updated,created,title". A leading - sorts that field in
+// descending order.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortFields[key.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q (use %s)", key.Field, strings.Join(SortFieldNames(), ", "))
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("sort requires at least one field")
+	}
+	return keys, nil
+}
+
+// SortFieldNames returns the fields --sort accepts, alphabetically.
+func SortFieldNames() []string {
+	names := make([]string, 0, len(sortFields))
+	for name := range sortFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IssueLess reports whether left sorts before right under keys, falling back
+// to the issue ID so the order is deterministic.
+func IssueLess(left, right Issue, keys []SortKey) bool {
+	for _, key := range keys {
+		cmp, leftUnset, rightUnset := sortFields[key.Field](left, right)
+		if leftUnset != rightUnset {
+			return rightUnset
+		}
+		if cmp == 0 {
+			continue
+		}
+		if key.Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	return left.ID < right.ID
+}
+
+// SortIssues orders issues by keys (see IssueLess).
+func SortIssues(issues []Issue, keys []SortKey) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return IssueLess(issues[i], issues[j], keys)
+	})
+}
@@ -0,0 +1,72 @@
+package pebbles
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortIssuesByKeys(t *testing.T) {
+	issues := []Issue{
+		{ID: "pb-a", Title: "beta", Priority: 2, UpdatedAt: "2024-01-02T00:00:00Z", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "pb-b", Title: "Alpha", Priority: 1, UpdatedAt: "2024-01-01T00:00:00Z", CreatedAt: "2024-01-02T00:00:00Z"},
+		{ID: "pb-c", Title: "gamma", Priority: 2, UpdatedAt: "2024-01-03T00:00:00Z", CreatedAt: "2024-01-03T00:00:00Z", DueAt: "2024-02-01"},
+		{ID: "pb-d", Title: "delta", Priority: 2, UpdatedAt: "2024-01-03T00:00:00Z", CreatedAt: "2024-01-01T00:00:00Z"},
+	}
+	cases := map[string]string{
+		"priority,-updated,created": "pb-b pb-d pb-c pb-a",
+		"title":                     "pb-b pb-a pb-d pb-c",
+		"-title":                    "pb-c pb-d pb-a pb-b",
+		// Unset due dates sort last in both directions; ties fall back to ID.
+		"due":  "pb-c pb-a pb-b pb-d",
+		"-due": "pb-c pb-a pb-b pb-d",
+	}
+	for spec, want := range cases {
+		keys, err := ParseSortKeys(spec)
+		if err != nil {
+			t.Fatalf("%q: parse: %v", spec, err)
+		}
+		sorted := append([]Issue(nil), issues...)
+		SortIssues(sorted, keys)
+		ids := make([]string, 0, len(sorted))
+		for _, issue := range sorted {
+			ids = append(ids, issue.ID)
+		}
+		if got := strings.Join(ids, " "); got != want {
+			t.Fatalf("%q: expected %q, got %q", spec, want, got)
+		}
+	}
+}
+
+func TestSortIssuesComparesTimestampsAsInstants(t *testing.T) {
+	// As strings these would order pb-b, pb-c, pb-a: fraction lengths and
+	// offsets differ even though pb-a is the earliest instant.
+	issues := []Issue{
+		{ID: "pb-a", CreatedAt: "2024-01-01T10:00:00+02:00"},
+		{ID: "pb-b", CreatedAt: "2024-01-01T09:00:00.5Z"},
+		{ID: "pb-c", CreatedAt: "2024-01-01T09:00:00Z"},
+	}
+	keys, err := ParseSortKeys("created")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	SortIssues(issues, keys)
+	ids := []string{issues[0].ID, issues[1].ID, issues[2].ID}
+	if got := strings.Join(ids, " "); got != "pb-a pb-c pb-b" {
+		t.Fatalf("expected created order pb-a pb-c pb-b, got %q", got)
+	}
+
+	SortReadyIssues(issues, time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC))
+	ids = []string{issues[0].ID, issues[1].ID, issues[2].ID}
+	if got := strings.Join(ids, " "); got != "pb-a pb-c pb-b" {
+		t.Fatalf("expected ready order pb-a pb-c pb-b, got %q", got)
+	}
+}
+
+func TestParseSortKeysRejectsUnknownFields(t *testing.T) {
+	for _, bad := range []string{"size", ",", "priority,-nope"} {
+		if _, err := ParseSortKeys(bad); err == nil {
+			t.Fatalf("expected %q rejected", bad)
+		}
+	}
+}
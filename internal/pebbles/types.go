@@ -2,11 +2,19 @@ package pebbles
 
 import "strings"
 
+// EventSchemaVersion is the event format written by this version of pb.
+// Events from newer schemas are still read; types this version does not
+// recognise are skipped during replay instead of failing the rebuild.
+const EventSchemaVersion = 1
+
 // Event represents an append-only change in the Pebbles log.
 type Event struct {
+	Version   int               `json:"version,omitempty"`
+	Clock     int64             `json:"clock,omitempty"`
 	Type      string            `json:"type"`
 	Timestamp string            `json:"timestamp"`
 	IssueID   string            `json:"issue_id"`
+	Actor     string            `json:"actor,omitempty"`
 	Payload   map[string]string `json:"payload"`
 }
 
@@ -21,13 +29,62 @@ type Issue struct {
 	CreatedAt   string
 	UpdatedAt   string
 	ClosedAt    string
+	// Resolution and CloseReason come from the latest close event.
+	Resolution  string
+	CloseReason string
+	Assignee    string
+	Labels      []string
+	// Fields holds custom field values by name; unset fields are absent.
+	Fields map[string]string
+	// DueAt is the due date as YYYY-MM-DD, empty when none is set.
+	DueAt string
+	// DeferredUntil is the YYYY-MM-DD wake-up date of the latest deferral;
+	// it may have passed.
+	DeferredUntil string
+	// Estimate is story points ("3") or a duration ("4h"), empty when unset.
+	Estimate string
+	// LoggedMinutes totals the issue's work_log events.
+	LoggedMinutes int
+	// TimerStartedAt and TimerStartedBy describe the running work timer;
+	// both are empty when no timer runs.
+	TimerStartedAt string
+	TimerStartedBy string
+	// ClaimedBy and ClaimExpiresAt describe the latest claim; it may have expired.
+	ClaimedBy      string
+	ClaimExpiresAt string
+	// DeletedAt is set while the issue is deleted; deleted issues are hidden.
+	DeletedAt    string
+	DeleteReason string
 }
 
 // IssueComment represents a user-authored comment on an issue.
 type IssueComment struct {
+	ID        string
 	IssueID   string
 	Body      string
 	Timestamp string
+	Actor     string
+	// EditedAt and EditedBy describe the latest edit; History holds the
+	// earlier bodies, oldest first.
+	EditedAt string
+	EditedBy string
+	History  []CommentRevision
+}
+
+// CommentRevision is an earlier body of an edited comment.
+type CommentRevision struct {
+	Body      string
+	Timestamp string
+	Actor     string
+}
+
+// SkippedEvent records an event the cache replay could not apply.
+type SkippedEvent struct {
+	Type      string
+	IssueID   string
+	Timestamp string
+	Version   int
+	Reason    string
 }
 
 // IssueHierarchyItem represents an issue with its indentation depth.
@@ -44,7 +101,12 @@ type BlockedIssue struct {
 
 // Config stores per-project Pebbles settings.
 type Config struct {
-	Prefix string `json:"prefix"`
+	Prefix     string            `json:"prefix"`
+	Actor      string            `json:"actor,omitempty"`
+	Statuses   []StatusConfig    `json:"statuses,omitempty"`
+	IssueTypes []IssueTypeConfig `json:"issue_types,omitempty"`
+	Fields     []FieldConfig     `json:"fields,omitempty"`
+	Views      []ViewConfig      `json:"views,omitempty"`
 }
 
 const (
@@ -66,6 +128,39 @@ const (
 	EventTypeDepAdd = "dep_add"
 	// EventTypeDepRemove indicates a dependency removal event.
 	EventTypeDepRemove = "dep_rm"
+	// EventTypeLabelAdd indicates a label add event.
+	EventTypeLabelAdd = "label_add"
+	// EventTypeLabelRemove indicates a label removal event.
+	EventTypeLabelRemove = "label_rm"
+	// EventTypeAssign indicates an assignee change; an empty assignee unassigns.
+	EventTypeAssign = "assign"
+	// EventTypeClaim indicates a leased claim by the event actor.
+	EventTypeClaim = "claim"
+	// EventTypeRelease indicates a claim was given back.
+	EventTypeRelease = "release"
+	// EventTypeDelete indicates an issue was deleted (hidden, not erased).
+	EventTypeDelete = "delete"
+	// EventTypeUndelete indicates a deleted issue was restored.
+	EventTypeUndelete = "undelete"
+	// EventTypeCommentEdit indicates a comment body was replaced.
+	EventTypeCommentEdit = "comment_edit"
+	// EventTypeCommentDelete indicates a comment was removed.
+	EventTypeCommentDelete = "comment_delete"
+	// EventTypeFieldSet sets a custom field; an empty value clears it.
+	EventTypeFieldSet = "field_set"
+	// EventTypeDue sets an issue's due date; an empty due_at clears it.
+	EventTypeDue = "due"
+	// EventTypeDefer hides an issue from ready work until a date.
+	EventTypeDefer = "defer"
+	// EventTypeUndefer clears a deferral early.
+	EventTypeUndefer = "undefer"
+	// EventTypeEstimate sets an issue's estimate; an empty estimate clears it.
+	EventTypeEstimate = "estimate"
+	// EventTypeTimerStart starts a work timer on an issue.
+	EventTypeTimerStart = "timer_start"
+	// EventTypeWorkLog records minutes worked on an issue. With started_at it
+	// also stops the running timer.
+	EventTypeWorkLog = "work_log"
 )
 
 const (
@@ -73,6 +168,19 @@ const (
 	DepTypeBlocks = "blocks"
 	// DepTypeParentChild indicates a parent-child relationship.
 	DepTypeParentChild = "parent-child"
+	// DepTypeDuplicateOf marks an issue as a duplicate of another issue.
+	DepTypeDuplicateOf = "duplicate-of"
+)
+
+const (
+	// ResolutionDone indicates the work was completed.
+	ResolutionDone = "done"
+	// ResolutionWontFix indicates the issue was closed without doing the work.
+	ResolutionWontFix = "wontfix"
+	// ResolutionDuplicate indicates the issue duplicates another issue.
+	ResolutionDuplicate = "duplicate"
+	// ResolutionObsolete indicates the issue no longer applies.
+	ResolutionObsolete = "obsolete"
 )
 
 const (
@@ -0,0 +1,167 @@
+package pebbles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ViewFormatLines prints the usual pb list lines.
+	ViewFormatLines = "lines"
+	// ViewFormatTable prints the view's columns under a header.
+	ViewFormatTable = "table"
+	// ViewFormatJSON prints the pb list JSON array.
+	ViewFormatJSON = "json"
+)
+
+// ViewColumns lists the built-in columns a table view can show; declared
+// custom fields are accepted as columns too.
+var ViewColumns = []string{
+	"id", "title", "status", "type", "priority", "assignee", "labels",
+	"due", "deferred", "created", "updated", "closed", "estimate", "logged",
+}
+
+// ViewConfig declares a saved view in config.json or views.local.json.
+// Filters combine: the ready queue or every issue, narrowed by mine and
+// where, then sorted and limited like pb list.
+type ViewConfig struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Ready       bool     `json:"ready,omitempty"`
+	All         bool     `json:"all,omitempty"`
+	Mine        bool     `json:"mine,omitempty"`
+	Where       string   `json:"where,omitempty"`
+	Sort        string   `json:"sort,omitempty"`
+	Limit       int      `json:"limit,omitempty"`
+	Columns     []string `json:"columns,omitempty"`
+	Format      string   `json:"format,omitempty"`
+}
+
+// View is a validated saved view. Personal views come from the untracked
+// views.local.json and replace shared views of the same name.
+type View struct {
+	ViewConfig
+	Personal bool
+}
+
+// viewsFile is the layout of views.local.json.
+type viewsFile struct {
+	Views []ViewConfig `json:"views"`
+}
+
+// LoadViews reads the shared views from config.json and the personal views
+// from views.local.json, sorted by name.
+func LoadViews(root string, customFields CustomFields) ([]View, error) {
+	cfg, err := LoadConfig(root)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var personal viewsFile
+	data, err := os.ReadFile(ViewsLocalPath(root))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read views.local.json: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &personal); err != nil {
+			return nil, fmt.Errorf("parse views.local.json: %w", err)
+		}
+	}
+	return NewViews(cfg.Views, personal.Views, customFields)
+}
+
+// NewViews validates shared and personal view declarations and merges them,
+// letting a personal view replace the shared view with its name.
+func NewViews(shared, personal []ViewConfig, customFields CustomFields) ([]View, error) {
+	byName := make(map[string]View)
+	for _, source := range []struct {
+		configs  []ViewConfig
+		personal bool
+		file     string
+	}{{shared, false, "config"}, {personal, true, "views.local.json"}} {
+		seen := make(map[string]bool)
+		for _, config := range source.configs {
+			view, err := newView(config, customFields)
+			if err != nil {
+				return nil, fmt.Errorf("%s views: %w", source.file, err)
+			}
+			if seen[view.Name] {
+				return nil, fmt.Errorf("%s views: view %s is declared twice", source.file, view.Name)
+			}
+			seen[view.Name] = true
+			byName[view.Name] = View{ViewConfig: view, Personal: source.personal}
+		}
+	}
+	views := make([]View, 0, len(byName))
+	for _, view := range byName {
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+// newView validates one view and returns it in normalized form.
+func newView(config ViewConfig, customFields CustomFields) (ViewConfig, error) {
+	name := strings.ToLower(strings.TrimSpace(config.Name))
+	if name == "" {
+		return ViewConfig{}, fmt.Errorf("view name is required")
+	}
+	if strings.ContainsAny(name, ", \t\n") {
+		return ViewConfig{}, fmt.Errorf("view %q cannot contain commas or spaces", config.Name)
+	}
+	// "pb view list" lists views, so no view can be called list.
+	if name == "list" {
+		return ViewConfig{}, fmt.Errorf("view name list is reserved")
+	}
+	config.Name = name
+	if strings.TrimSpace(config.Where) != "" {
+		if _, err := ParseWhere(config.Where, customFields, time.Now()); err != nil {
+			return ViewConfig{}, fmt.Errorf("view %s: %w", name, err)
+		}
+	}
+	if strings.TrimSpace(config.Sort) != "" {
+		if _, err := ParseSortKeys(config.Sort); err != nil {
+			return ViewConfig{}, fmt.Errorf("view %s: %w", name, err)
+		}
+	}
+	if config.Limit < 0 {
+		return ViewConfig{}, fmt.Errorf("view %s: limit must not be negative", name)
+	}
+	columns := make([]string, 0, len(config.Columns))
+	for _, column := range config.Columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := customFields.Lookup(column); !ok && !containsString(ViewColumns, column) {
+			return ViewConfig{}, fmt.Errorf("view %s has unknown column %q (use %s, or a custom field)", name, column, strings.Join(ViewColumns, ", "))
+		}
+		columns = append(columns, column)
+	}
+	config.Columns = columns
+	config.Format = strings.ToLower(strings.TrimSpace(config.Format))
+	switch config.Format {
+	case "":
+		// Columns only show in a table, so asking for them implies one.
+		config.Format = ViewFormatLines
+		if len(columns) > 0 {
+			config.Format = ViewFormatTable
+		}
+	case ViewFormatLines, ViewFormatTable, ViewFormatJSON:
+	default:
+		return ViewConfig{}, fmt.Errorf("view %s has unknown format %q (use lines, table, or json)", name, config.Format)
+	}
+	return config, nil
+}
+
+// FindView returns the view with a name, matching case-insensitively.
+func FindView(views []View, name string) (View, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, view := range views {
+		if view.Name == name {
+			return view, true
+		}
+	}
+	return View{}, false
+}
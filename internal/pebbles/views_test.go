@@ -0,0 +1,80 @@
+package pebbles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadViewsMergesPersonalViews(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Views = []ViewConfig{
+		{Name: "Triage", Where: "priority = P0", Columns: []string{"ID", "title"}},
+		{Name: "mine", Mine: true, Description: "shared"},
+	}
+	if err := WriteConfig(root, cfg); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	local := `{"views": [{"name": "mine", "mine": true, "description": "personal", "format": "json"}]}`
+	if err := os.WriteFile(ViewsLocalPath(root), []byte(local), 0600); err != nil {
+		t.Fatalf("write views.local.json: %v", err)
+	}
+
+	views, err := LoadViews(root, CustomFields{})
+	if err != nil {
+		t.Fatalf("load views: %v", err)
+	}
+	if len(views) != 2 || views[0].Name != "mine" || views[1].Name != "triage" {
+		t.Fatalf("expected views mine and triage, got %+v", views)
+	}
+	if !views[0].Personal || views[0].Description != "personal" || views[0].Format != ViewFormatJSON {
+		t.Fatalf("expected the personal view to replace the shared one, got %+v", views[0])
+	}
+	triage, ok := FindView(views, "TRIAGE")
+	if !ok || triage.Format != ViewFormatTable || strings.Join(triage.Columns, ",") != "id,title" {
+		t.Fatalf("expected columns to imply a table view, got %+v", triage)
+	}
+
+	data, err := os.ReadFile(filepath.Join(PebblesDir(root), ".gitignore"))
+	if err != nil || !strings.Contains(string(data), "views.local.json") {
+		t.Fatalf("expected views.local.json to be git-ignored: %q (%v)", data, err)
+	}
+}
+
+func TestNewViewsRejectsInvalidViews(t *testing.T) {
+	customFields, err := NewCustomFields([]FieldConfig{{Name: "component", Type: FieldTypeString}})
+	if err != nil {
+		t.Fatalf("custom fields: %v", err)
+	}
+	if _, err := NewViews([]ViewConfig{{Name: "ok", Columns: []string{"component"}}}, nil, customFields); err != nil {
+		t.Fatalf("expected a custom field column to be accepted: %v", err)
+	}
+	cases := map[string]ViewConfig{
+		"name is required": {Name: " "},
+		"reserved":         {Name: "list"},
+		"unknown field":    {Name: "bad", Where: "size = 3"},
+		"unknown sort":     {Name: "bad", Sort: "size"},
+		"negative":         {Name: "bad", Limit: -1},
+		"unknown column":   {Name: "bad", Columns: []string{"size"}},
+		"unknown format":   {Name: "bad", Format: "csv"},
+		"commas or spaces": {Name: "my view"},
+	}
+	for want, view := range cases {
+		_, err := NewViews([]ViewConfig{view}, nil, customFields)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%+v: expected error containing %q, got %v", view, want, err)
+		}
+	}
+	_, err = NewViews(nil, []ViewConfig{{Name: "a"}, {Name: "A"}}, customFields)
+	if err == nil || !strings.Contains(err.Error(), "views.local.json views: view a is declared twice") {
+		t.Fatalf("expected duplicate personal views rejected, got %v", err)
+	}
+}
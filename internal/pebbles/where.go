@@ -0,0 +1,678 @@
+package pebbles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// WhereKind is the value type of a field in a --where expression.
+type WhereKind int
+
+const (
+	// WhereText compares case-insensitive strings with =, !=, ~, and in.
+	WhereText WhereKind = iota
+	// WherePriority compares P0-P4 with every operator; P0 < P1.
+	WherePriority
+	// WhereTime compares RFC3339 timestamps against dates or offsets like -7d.
+	WhereTime
+	// WhereDate compares YYYY-MM-DD dates; unset dates match only = none.
+	WhereDate
+	// WhereInt compares whole numbers.
+	WhereInt
+	// WhereDuration compares minutes written as durations like 2h.
+	WhereDuration
+	// WhereLabel matches when any of an issue's labels matches.
+	WhereLabel
+)
+
+// WhereField is a field a --where expression can reference, resolved to the
+// SQL expression that reads it from the issues table aliased as i.
+type WhereField struct {
+	Name string
+	Kind WhereKind
+	sql  string
+	args []any
+}
+
+// WhereExpr is a node of a parsed --where expression.
+type WhereExpr interface {
+	// whereSQL appends the node's arguments and returns its SQL condition.
+	whereSQL(args *[]any) string
+}
+
+// WhereAnd matches when both sides match.
+type WhereAnd struct {
+	Left, Right WhereExpr
+}
+
+// WhereOr matches when either side matches.
+type WhereOr struct {
+	Left, Right WhereExpr
+}
+
+// WhereNot matches when the inner expression does not.
+type WhereNot struct {
+	Expr WhereExpr
+}
+
+// WhereComparison compares a field with one value, or with a list for
+// "in" and "not in". Values are already typed: strings for text, labels,
+// and dates, ints for priorities, numbers, and durations, and whereTime
+// bounds for timestamps.
+type WhereComparison struct {
+	Field  WhereField
+	Op     string
+	Values []any
+}
+
+// whereTime is a timestamp bound: an instant, or a whole local day when end
+// is set.
+type whereTime struct {
+	start string
+	end   string
+}
+
+// whereBuiltinFields maps built-in field names to their kind and column.
+var whereBuiltinFields = map[string]WhereField{
+	"id":          {Kind: WhereText, sql: "i.id"},
+	"title":       {Kind: WhereText, sql: "i.title"},
+	"description": {Kind: WhereText, sql: "i.description"},
+	"type":        {Kind: WhereText, sql: "i.issue_type"},
+	"status":      {Kind: WhereText, sql: "i.status"},
+	"assignee":    {Kind: WhereText, sql: "i.assignee"},
+	"resolution":  {Kind: WhereText, sql: "i.resolution"},
+	"priority":    {Kind: WherePriority, sql: "i.priority"},
+	"created":     {Kind: WhereTime, sql: "i.created_at"},
+	"updated":     {Kind: WhereTime, sql: "i.updated_at"},
+	"closed":      {Kind: WhereTime, sql: "i.closed_at"},
+	"due":         {Kind: WhereDate, sql: "i.due_at"},
+	"deferred":    {Kind: WhereDate, sql: "i.deferred_until"},
+	"logged":      {Kind: WhereDuration, sql: "i.logged_minutes"},
+	"label":       {Kind: WhereLabel},
+	"parent": {Kind: WhereText, sql: "COALESCE((SELECT d.depends_on_id FROM deps d WHERE d.issue_id = i.id AND d.dep_type = ? ORDER BY d.depends_on_id LIMIT 1), '')",
+		args: []any{DepTypeParentChild}},
+	"children": {Kind: WhereInt, sql: "(SELECT COUNT(*) FROM deps d WHERE d.depends_on_id = i.id AND d.dep_type = ?)",
+		args: []any{DepTypeParentChild}},
+}
+
+// ParseWhere parses a --where expression such as
+// "status in (open,in_progress) and priority <= P1 and updated > -7d" into
+// a typed AST. Field names resolve to built-in fields first, then to the
+// project's custom fields; relative dates resolve against now.
+func ParseWhere(input string, customFields CustomFields, now time.Time) (WhereExpr, error) {
+	tokens, err := lexWhere(input)
+	if err != nil {
+		return nil, err
+	}
+	parser := &whereParser{tokens: tokens, customFields: customFields, now: now}
+	if parser.peek().kind == whereTokenEOF {
+		return nil, fmt.Errorf("where: expression is empty")
+	}
+	expr, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.kind != whereTokenEOF {
+		return nil, fmt.Errorf("where: unexpected %q at position %d", token.text, token.pos+1)
+	}
+	return expr, nil
+}
+
+// WhereSQL compiles an expression into an SQL condition over the issues
+// table aliased as i, with its positional arguments.
+func WhereSQL(expr WhereExpr) (string, []any) {
+	var args []any
+	condition := expr.whereSQL(&args)
+	return condition, args
+}
+
+// WhereFields returns the names of the fields an expression references.
+func WhereFields(expr WhereExpr) []string {
+	switch node := expr.(type) {
+	case WhereAnd:
+		return append(WhereFields(node.Left), WhereFields(node.Right)...)
+	case WhereOr:
+		return append(WhereFields(node.Left), WhereFields(node.Right)...)
+	case WhereNot:
+		return WhereFields(node.Expr)
+	case WhereComparison:
+		return []string{node.Field.Name}
+	}
+	return nil
+}
+
+// FilterIssueIDs returns the IDs of live issues matching an expression.
+func FilterIssueIDs(root string, expr WhereExpr) (map[string]bool, error) {
+	if err := EnsureCache(root); err != nil {
+		return nil, err
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	condition, args := WhereSQL(expr)
+	rows, err := db.Query("SELECT i.id FROM issues i WHERE i.deleted_at = '' AND ("+condition+")", args...)
+	if err != nil {
+		return nil, fmt.Errorf("where query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan where result: %w", err)
+		}
+		ids[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("where rows: %w", err)
+	}
+	return ids, nil
+}
+
+// whereSQL joins both sides with AND.
+func (node WhereAnd) whereSQL(args *[]any) string {
+	left := node.Left.whereSQL(args)
+	return "(" + left + " AND " + node.Right.whereSQL(args) + ")"
+}
+
+// whereSQL joins both sides with OR.
+func (node WhereOr) whereSQL(args *[]any) string {
+	left := node.Left.whereSQL(args)
+	return "(" + left + " OR " + node.Right.whereSQL(args) + ")"
+}
+
+// whereSQL negates the inner condition; an inner SQL NULL counts as false,
+// so comparisons against unset values are matched by not.
+func (node WhereNot) whereSQL(args *[]any) string {
+	return "NOT COALESCE(" + node.Expr.whereSQL(args) + ", 0)"
+}
+
+// whereSQL renders one comparison for its field kind. Arguments are appended
+// in the order their placeholders appear.
+func (node WhereComparison) whereSQL(args *[]any) string {
+	field := node.Field
+	column := func() string {
+		*args = append(*args, field.args...)
+		return field.sql
+	}
+	bind := func(values ...any) {
+		*args = append(*args, values...)
+	}
+	switch field.Kind {
+	case WhereLabel:
+		exists := "EXISTS (SELECT 1 FROM labels l WHERE l.issue_id = i.id AND l.label "
+		switch node.Op {
+		case "~":
+			bind(likePattern(node.Values[0].(string)))
+			return exists + "LIKE ? ESCAPE '\\')"
+		case "!=", "not in":
+			bind(node.Values...)
+			return "NOT " + exists + "IN (" + sqlPlaceholders(len(node.Values)) + "))"
+		default:
+			bind(node.Values...)
+			return exists + "IN (" + sqlPlaceholders(len(node.Values)) + "))"
+		}
+	case WhereTime:
+		bound := node.Values[0].(whereTime)
+		value := "julianday(" + column() + ")"
+		if bound.end == "" {
+			bind(bound.start)
+			return value + " " + node.Op + " julianday(?)"
+		}
+		// A whole day covers [start, end).
+		switch node.Op {
+		case "=", "!=":
+			condition := value + " >= julianday(?) AND "
+			bind(bound.start)
+			condition += "julianday(" + column() + ") < julianday(?)"
+			bind(bound.end)
+			if node.Op == "!=" {
+				return "NOT (" + condition + ")"
+			}
+			return "(" + condition + ")"
+		case ">", "<=":
+			bind(bound.end)
+			return value + " " + map[string]string{">": ">=", "<=": "<"}[node.Op] + " julianday(?)"
+		default:
+			bind(bound.start)
+			return value + " " + node.Op + " julianday(?)"
+		}
+	}
+	// Unset dates never compare as before or after a date.
+	guard := ""
+	if field.Kind == WhereDate && node.Op != "=" && node.Op != "!=" {
+		guard = column() + " != '' AND "
+	}
+	expr := column()
+	if field.Kind == WhereText {
+		expr += " COLLATE NOCASE"
+	}
+	var condition string
+	switch node.Op {
+	case "~":
+		bind(likePattern(node.Values[0].(string)))
+		condition = expr + " LIKE ? ESCAPE '\\'"
+	case "in", "not in":
+		bind(node.Values...)
+		condition = expr + " " + strings.ToUpper(node.Op) + " (" + sqlPlaceholders(len(node.Values)) + ")"
+	default:
+		bind(node.Values[0])
+		condition = expr + " " + node.Op + " ?"
+	}
+	return "(" + guard + condition + ")"
+}
+
+// likePattern builds a LIKE pattern matching a substring.
+func likePattern(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+	return "%" + escaped + "%"
+}
+
+const (
+	whereTokenEOF = iota
+	whereTokenWord
+	whereTokenString
+	whereTokenOp
+	whereTokenLParen
+	whereTokenRParen
+	whereTokenComma
+)
+
+// whereToken is one lexical token with its byte position in the input.
+type whereToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+// lexWhere splits an expression into words, quoted strings, operators,
+// parentheses, and commas.
+func lexWhere(input string) ([]whereToken, error) {
+	var tokens []whereToken
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			tokens = append(tokens, whereToken{kind: whereTokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, whereToken{kind: whereTokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, whereToken{kind: whereTokenComma, text: ",", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(input) && input[j] != c; j++ {
+				if input[j] == '\\' && j+1 < len(input) {
+					j++
+				}
+				text.WriteByte(input[j])
+			}
+			if j >= len(input) {
+				return nil, fmt.Errorf("where: unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, whereToken{kind: whereTokenString, text: text.String(), pos: i})
+			i = j + 1
+		case strings.ContainsRune("=!<>~", rune(c)):
+			op := string(c)
+			if i+1 < len(input) && input[i+1] == '=' && c != '~' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("where: unexpected \"!\" at position %d (use != or not)", i+1)
+			}
+			tokens = append(tokens, whereToken{kind: whereTokenOp, text: op, pos: i})
+			i += len(op)
+		default:
+			j := i
+			for j < len(input) && !unicode.IsSpace(rune(input[j])) && !strings.ContainsRune("(),=!<>~\"'", rune(input[j])) {
+				j++
+			}
+			tokens = append(tokens, whereToken{kind: whereTokenWord, text: input[i:j], pos: i})
+			i = j
+		}
+	}
+	return append(tokens, whereToken{kind: whereTokenEOF, text: "end of expression", pos: len(input)}), nil
+}
+
+// whereParser is a recursive-descent parser over lexed tokens. Precedence
+// from loosest to tightest is or, and, not, then comparisons.
+type whereParser struct {
+	tokens       []whereToken
+	index        int
+	customFields CustomFields
+	now          time.Time
+}
+
+// peek returns the current token without consuming it.
+func (parser *whereParser) peek() whereToken {
+	return parser.tokens[parser.index]
+}
+
+// next consumes and returns the current token.
+func (parser *whereParser) next() whereToken {
+	token := parser.tokens[parser.index]
+	if token.kind != whereTokenEOF {
+		parser.index++
+	}
+	return token
+}
+
+// keyword reports whether the current token is a case-insensitive keyword.
+func (parser *whereParser) keyword(word string) bool {
+	token := parser.peek()
+	return token.kind == whereTokenWord && strings.EqualFold(token.text, word)
+}
+
+// parseOr parses "a or b or ...".
+func (parser *whereParser) parseOr() (WhereExpr, error) {
+	left, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for parser.keyword("or") {
+		parser.next()
+		right, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = WhereOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses "a and b and ...".
+func (parser *whereParser) parseAnd() (WhereExpr, error) {
+	left, err := parser.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for parser.keyword("and") {
+		parser.next()
+		right, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = WhereAnd{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses an optional "not" prefix.
+func (parser *whereParser) parseNot() (WhereExpr, error) {
+	if parser.keyword("not") {
+		parser.next()
+		expr, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return WhereNot{Expr: expr}, nil
+	}
+	return parser.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized expression or a comparison.
+func (parser *whereParser) parsePrimary() (WhereExpr, error) {
+	token := parser.next()
+	switch token.kind {
+	case whereTokenLParen:
+		expr, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := parser.next(); closing.kind != whereTokenRParen {
+			return nil, fmt.Errorf("where: expected \")\" at position %d, got %q", closing.pos+1, closing.text)
+		}
+		return expr, nil
+	case whereTokenWord:
+		return parser.parseComparison(token)
+	}
+	return nil, fmt.Errorf("where: expected a field name at position %d, got %q", token.pos+1, token.text)
+}
+
+// parseComparison parses "field op value" or "field [not] in (values)".
+func (parser *whereParser) parseComparison(name whereToken) (WhereExpr, error) {
+	field, err := parser.lookupField(name.text)
+	if err != nil {
+		return nil, err
+	}
+	var op string
+	switch token := parser.peek(); {
+	case token.kind == whereTokenOp:
+		op = parser.next().text
+	case parser.keyword("in"):
+		parser.next()
+		op = "in"
+	case parser.keyword("not"):
+		parser.next()
+		if !parser.keyword("in") {
+			return nil, fmt.Errorf("where: expected \"in\" after %s not", name.text)
+		}
+		parser.next()
+		op = "not in"
+	default:
+		return nil, fmt.Errorf("where: expected an operator after %s at position %d, got %q", name.text, token.pos+1, token.text)
+	}
+	if op == "==" {
+		op = "="
+	}
+	if err := checkWhereOperator(field, op); err != nil {
+		return nil, err
+	}
+	var raw []string
+	if op == "in" || op == "not in" {
+		if open := parser.next(); open.kind != whereTokenLParen {
+			return nil, fmt.Errorf("where: expected \"(\" after %s %s", name.text, op)
+		}
+		for {
+			value, err := parser.parseValue(name.text)
+			if err != nil {
+				return nil, err
+			}
+			raw = append(raw, value)
+			separator := parser.next()
+			if separator.kind == whereTokenRParen {
+				break
+			}
+			if separator.kind != whereTokenComma {
+				return nil, fmt.Errorf("where: expected \",\" or \")\" at position %d, got %q", separator.pos+1, separator.text)
+			}
+		}
+	} else {
+		value, err := parser.parseValue(name.text)
+		if err != nil {
+			return nil, err
+		}
+		raw = []string{value}
+	}
+	values := make([]any, 0, len(raw))
+	for _, value := range raw {
+		typed, err := parser.typedValue(field, op, value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, typed)
+	}
+	return WhereComparison{Field: field, Op: op, Values: values}, nil
+}
+
+// parseValue consumes a bare word or quoted string.
+func (parser *whereParser) parseValue(fieldName string) (string, error) {
+	token := parser.next()
+	if token.kind != whereTokenWord && token.kind != whereTokenString {
+		return "", fmt.Errorf("where: expected a value for %s at position %d, got %q", fieldName, token.pos+1, token.text)
+	}
+	return token.text, nil
+}
+
+// lookupField resolves a field name to a built-in or custom field.
+func (parser *whereParser) lookupField(name string) (WhereField, error) {
+	lower := strings.ToLower(name)
+	if field, ok := whereBuiltinFields[lower]; ok {
+		field.Name = lower
+		return field, nil
+	}
+	custom, ok := parser.customFields.Lookup(lower)
+	if !ok {
+		return WhereField{}, fmt.Errorf("where: unknown field %q", name)
+	}
+	value := "COALESCE((SELECT f.value FROM fields f WHERE f.issue_id = i.id AND f.name = ?), '')"
+	field := WhereField{Name: custom.Name, Kind: WhereText, sql: value, args: []any{custom.Name}}
+	switch custom.Type {
+	case FieldTypeInt:
+		field.Kind = WhereInt
+		field.sql = "CAST(NULLIF(" + value + ", '') AS INTEGER)"
+	case FieldTypeDate:
+		field.Kind = WhereDate
+	}
+	return field, nil
+}
+
+// checkWhereOperator rejects operators that do not apply to a field kind.
+func checkWhereOperator(field WhereField, op string) error {
+	ordered := op == "<" || op == "<=" || op == ">" || op == ">="
+	switch field.Kind {
+	case WhereText, WhereLabel:
+		if ordered {
+			return fmt.Errorf("where: %s does not support %s (use =, !=, ~, or in)", field.Name, op)
+		}
+	case WhereTime:
+		if op == "~" || op == "in" || op == "not in" {
+			return fmt.Errorf("where: %s does not support %s (use =, !=, <, <=, >, or >=)", field.Name, op)
+		}
+	default:
+		if op == "~" {
+			return fmt.Errorf("where: %s does not support ~", field.Name)
+		}
+	}
+	return nil
+}
+
+// typedValue converts a raw value to the Go type a field kind compares with.
+func (parser *whereParser) typedValue(field WhereField, op, value string) (any, error) {
+	switch field.Kind {
+	case WhereLabel:
+		return strings.ToLower(strings.TrimSpace(value)), nil
+	case WherePriority:
+		if strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("where: %s needs a priority (P0-P4)", field.Name)
+		}
+		priority, err := ParsePriority(value)
+		if err != nil {
+			return nil, fmt.Errorf("where: %s: %w", field.Name, err)
+		}
+		return priority, nil
+	case WhereInt:
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("where: %s: %q is not a whole number", field.Name, value)
+		}
+		return number, nil
+	case WhereDuration:
+		if strings.TrimSpace(value) == "0" {
+			return 0, nil
+		}
+		minutes, err := ParseWorkDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("where: %s: %w", field.Name, err)
+		}
+		return minutes, nil
+	case WhereDate:
+		date, err := parseWhereDate(value, parser.now)
+		if err != nil {
+			return nil, fmt.Errorf("where: %s: %w", field.Name, err)
+		}
+		if date == "" && op != "=" && op != "!=" {
+			return nil, fmt.Errorf("where: %s %s needs a date", field.Name, op)
+		}
+		return date, nil
+	case WhereTime:
+		bound, err := parseWhereTime(value, parser.now)
+		if err != nil {
+			return nil, fmt.Errorf("where: %s: %w", field.Name, err)
+		}
+		return bound, nil
+	}
+	if op == "~" {
+		return value, nil
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// parseWhereDate resolves a date value: the forms ParseRelativeDate accepts,
+// plus "yesterday" and past offsets like -3d. "none" or an empty value is the
+// unset date.
+func parseWhereDate(value string, now time.Time) (string, error) {
+	lower := strings.ToLower(strings.TrimSpace(value))
+	if lower == "yesterday" {
+		return localDate(now).AddDate(0, 0, -1).Format(dateLayout), nil
+	}
+	if strings.HasPrefix(lower, "-") {
+		offset, err := parseWhereOffset(lower)
+		if err != nil || offset%(24*time.Hour) != 0 {
+			return "", fmt.Errorf("invalid date offset %q (use -Nd or -Nw)", value)
+		}
+		return localDate(now).AddDate(0, 0, int(offset/(24*time.Hour))).Format(dateLayout), nil
+	}
+	return ParseRelativeDate(value, now)
+}
+
+// parseWhereTime resolves a timestamp value. Offsets such as -7d or -12h and
+// RFC3339 timestamps are instants; dates and day names cover a whole local day.
+func parseWhereTime(value string, now time.Time) (whereTime, error) {
+	lower := strings.ToLower(strings.TrimSpace(value))
+	if lower == "now" {
+		return whereTime{start: now.UTC().Format(time.RFC3339Nano)}, nil
+	}
+	if strings.HasPrefix(lower, "-") || strings.HasPrefix(lower, "+") {
+		if offset, err := parseWhereOffset(lower); err == nil {
+			return whereTime{start: now.Add(offset).UTC().Format(time.RFC3339Nano)}, nil
+		}
+	}
+	if instant, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return whereTime{start: instant.UTC().Format(time.RFC3339Nano)}, nil
+	}
+	date, err := parseWhereDate(value, now)
+	if err != nil || date == "" {
+		return whereTime{}, fmt.Errorf("invalid time %q (use -7d, -12h, a date, today, yesterday, or an RFC3339 timestamp)", value)
+	}
+	day, err := time.ParseInLocation(dateLayout, date, now.Location())
+	if err != nil {
+		return whereTime{}, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	return whereTime{
+		start: day.UTC().Format(time.RFC3339Nano),
+		end:   day.AddDate(0, 0, 1).UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// parseWhereOffset parses a signed offset in minutes, hours, days, or weeks
+// such as -7d, +2w, or -90m.
+func parseWhereOffset(value string) (time.Duration, error) {
+	if len(value) < 3 {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	count, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	units := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	offset := time.Duration(count) * unit
+	if value[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
@@ -0,0 +1,171 @@
+package pebbles
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupWhereProject creates issues covering the fields --where can compare.
+func setupWhereProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	now := time.Now().UTC()
+	recent := now.Add(-2 * 24 * time.Hour).Format(time.RFC3339Nano)
+	old := now.Add(-30 * 24 * time.Hour).Format(time.RFC3339Nano)
+	events := []Event{
+		NewCreateEvent("pb-epic", "Checkout epic", "", "epic", old, 1),
+		NewCreateEvent("pb-bug", "Login bug", "Crash on submit", "bug", recent, 0),
+		NewCreateEvent("pb-old", "Old bug", "", "bug", old, 1),
+		NewCreateEvent("pb-task", "Write docs", "", "task", recent, 3),
+		NewDepAddEvent("pb-bug", "pb-epic", DepTypeParentChild, old),
+		NewLabelAddEvent("pb-bug", "frontend", recent),
+		NewStatusEvent("pb-task", StatusInProgress, recent),
+		NewDueEvent("pb-bug", localDate(time.Now()).AddDate(0, 0, -1).Format(dateLayout), recent),
+		NewWorkLogEvent("pb-task", 150, "", recent),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	return root
+}
+
+// TestFilterIssueIDsWithWhere verifies compiled expressions select the right issues.
+func TestFilterIssueIDsWithWhere(t *testing.T) {
+	root := setupWhereProject(t)
+	cases := map[string]string{
+		"status in (open,in_progress) and priority <= P1 and type = bug and updated > -7d": "pb-bug",
+		"type = bug and children = 0":                                   "pb-bug,pb-old",
+		"children > 0":                                                  "pb-epic",
+		"parent = pb-epic":                                              "pb-bug",
+		"label = frontend or logged >= 2h":                              "pb-bug,pb-task",
+		"not (type = bug or type = epic)":                               "pb-task",
+		"title ~ 'BUG' and created < -7d":                               "pb-old",
+		"due < today":                                                   "pb-bug",
+		"due = none and type != epic":                                   "pb-old,pb-task",
+		"priority not in (P0, P1)":                                      "pb-task",
+		"description ~ crash":                                           "pb-bug",
+		"created = today or created = yesterday":                        "",
+		"updated >= " + time.Now().AddDate(0, 0, -3).Format(dateLayout): "pb-bug,pb-task",
+	}
+	for input, want := range cases {
+		expr, err := ParseWhere(input, CustomFields{}, time.Now())
+		if err != nil {
+			t.Fatalf("ParseWhere(%q): %v", input, err)
+		}
+		ids, err := FilterIssueIDs(root, expr)
+		if err != nil {
+			t.Fatalf("FilterIssueIDs(%q): %v", input, err)
+		}
+		got := make([]string, 0, len(ids))
+		for id := range ids {
+			got = append(got, id)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != want {
+			t.Fatalf("%q matched %v, want %s", input, got, want)
+		}
+	}
+}
+
+// TestParseWhereErrors verifies syntax and type errors name the problem.
+func TestParseWhereErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                        "empty",
+		"status =":                "expected a value",
+		"status < open":           "does not support <",
+		"colour = red":            "unknown field",
+		"priority = P9":           "priority must be P0-P4",
+		"(type = bug":             "expected \")\"",
+		"type = bug and":          "expected a field name",
+		"updated > someday":       "invalid time",
+		"status in (open closed)": "expected \",\" or \")\"",
+		"title = 'unterminated":   "unterminated string",
+		"type = bug extra":        "unexpected \"extra\"",
+		"status not open":         "expected \"in\"",
+	}
+	for input, want := range cases {
+		_, err := ParseWhere(input, CustomFields{}, time.Now())
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParseWhere(%q) error = %v, want it to mention %q", input, err, want)
+		}
+	}
+}
+
+// TestWhereCustomFields verifies custom fields compare by their declared type.
+func TestWhereCustomFields(t *testing.T) {
+	root := setupWhereProject(t)
+	customFields, err := NewCustomFields([]FieldConfig{
+		{Name: "points", Type: FieldTypeInt},
+		{Name: "customer", Type: FieldTypeString},
+	})
+	if err != nil {
+		t.Fatalf("new custom fields: %v", err)
+	}
+	events := []Event{
+		NewFieldSetEvent("pb-bug", "points", "8", "2024-01-01T00:00:00Z"),
+		NewFieldSetEvent("pb-task", "points", "13", "2024-01-01T00:00:00Z"),
+		NewFieldSetEvent("pb-task", "customer", "Acme", "2024-01-01T00:00:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	expr, err := ParseWhere("points > 9 or customer = acme", customFields, time.Now())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	ids, err := FilterIssueIDs(root, expr)
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if len(ids) != 1 || !ids["pb-task"] {
+		t.Fatalf("expected only pb-task, got %v", ids)
+	}
+}
+
+// TestWhereBuiltinsCannotBeShadowedByCustomFields verifies a config cannot
+// declare a custom field --where would never reach, and that a stray value
+// stored under a built-in name does not change what the built-in matches.
+func TestWhereBuiltinsCannotBeShadowedByCustomFields(t *testing.T) {
+	root := setupWhereProject(t)
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	for _, name := range []string{"priority", "status", "created", "label", "parent", "children"} {
+		cfg.Fields = []FieldConfig{{Name: name, Type: FieldTypeString}}
+		if err := WriteConfig(root, cfg); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := LoadCustomFields(root); err == nil || !strings.Contains(err.Error(), "built-in") {
+			t.Fatalf("expected custom field %s rejected as built-in, got %v", name, err)
+		}
+	}
+	if err := AppendEvent(root, NewFieldSetEvent("pb-task", "priority", "0", "2024-01-01T00:00:00Z")); err != nil {
+		t.Fatalf("append field: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	expr, err := ParseWhere("priority <= P1", CustomFields{}, time.Now())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	ids, err := FilterIssueIDs(root, expr)
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if len(ids) != 3 || ids["pb-task"] {
+		t.Fatalf("expected the built-in priority to decide, got %v", ids)
+	}
+}
@@ -0,0 +1,200 @@
+package pebbles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// StatusCategoryTodo groups statuses for work that has not started.
+	StatusCategoryTodo = "todo"
+	// StatusCategoryActive groups statuses for work that is under way.
+	StatusCategoryActive = "active"
+	// StatusCategoryDone groups statuses for finished work.
+	StatusCategoryDone = "done"
+)
+
+// StatusConfig declares one workflow status in config.json. An empty
+// Transitions list allows moving to any status.
+type StatusConfig struct {
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Transitions []string `json:"transitions,omitempty"`
+}
+
+// Workflow is the validated set of statuses an issue can be in.
+type Workflow struct {
+	statuses []StatusConfig
+	index    map[string]int
+}
+
+// DefaultWorkflow returns the built-in open/in_progress/closed workflow.
+func DefaultWorkflow() Workflow {
+	workflow, _ := NewWorkflow([]StatusConfig{
+		{Name: StatusOpen, Category: StatusCategoryTodo},
+		{Name: StatusInProgress, Category: StatusCategoryActive},
+		{Name: StatusClosed, Category: StatusCategoryDone},
+	})
+	return workflow
+}
+
+// NewWorkflow validates status declarations. The open and closed statuses
+// must be declared because create, reopen, and close write them.
+func NewWorkflow(statuses []StatusConfig) (Workflow, error) {
+	workflow := Workflow{index: make(map[string]int, len(statuses))}
+	for _, status := range statuses {
+		name := strings.ToLower(strings.TrimSpace(status.Name))
+		if name == "" {
+			return Workflow{}, fmt.Errorf("workflow status name is required")
+		}
+		if strings.ContainsAny(name, ", \t\n") {
+			return Workflow{}, fmt.Errorf("workflow status %q cannot contain commas or spaces", status.Name)
+		}
+		if _, ok := workflow.index[name]; ok {
+			return Workflow{}, fmt.Errorf("workflow status %s is declared twice", name)
+		}
+		category := strings.ToLower(strings.TrimSpace(status.Category))
+		switch category {
+		case StatusCategoryTodo, StatusCategoryActive, StatusCategoryDone:
+		default:
+			return Workflow{}, fmt.Errorf("workflow status %s has unknown category %q (use todo, active, or done)", name, status.Category)
+		}
+		transitions := make([]string, 0, len(status.Transitions))
+		for _, next := range status.Transitions {
+			transitions = append(transitions, strings.ToLower(strings.TrimSpace(next)))
+		}
+		workflow.index[name] = len(workflow.statuses)
+		workflow.statuses = append(workflow.statuses, StatusConfig{Name: name, Category: category, Transitions: transitions})
+	}
+	// Transitions can only point at declared statuses.
+	for _, status := range workflow.statuses {
+		for _, next := range status.Transitions {
+			if !workflow.Known(next) {
+				return Workflow{}, fmt.Errorf("workflow status %s allows unknown status %q", status.Name, next)
+			}
+		}
+	}
+	if workflow.Category(StatusOpen) == StatusCategoryDone || !workflow.Known(StatusOpen) {
+		return Workflow{}, fmt.Errorf("workflow must declare %s with category todo or active", StatusOpen)
+	}
+	if !workflow.Known(StatusClosed) || workflow.Category(StatusClosed) != StatusCategoryDone {
+		return Workflow{}, fmt.Errorf("workflow must declare %s with category done", StatusClosed)
+	}
+	return workflow, nil
+}
+
+// LoadWorkflow reads the workflow from config.json, falling back to the
+// default when the project has no config or declares no statuses.
+func LoadWorkflow(root string) (Workflow, error) {
+	cfg, err := LoadConfig(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultWorkflow(), nil
+	}
+	if err != nil {
+		return Workflow{}, err
+	}
+	if len(cfg.Statuses) == 0 {
+		return DefaultWorkflow(), nil
+	}
+	workflow, err := NewWorkflow(cfg.Statuses)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("config statuses: %w", err)
+	}
+	return workflow, nil
+}
+
+// Statuses returns the declared status names in config order.
+func (workflow Workflow) Statuses() []string {
+	names := make([]string, 0, len(workflow.statuses))
+	for _, status := range workflow.statuses {
+		names = append(names, status.Name)
+	}
+	return names
+}
+
+// StatusesIn returns the declared statuses in a category, in config order.
+func (workflow Workflow) StatusesIn(category string) []string {
+	var names []string
+	for _, status := range workflow.statuses {
+		if status.Category == category {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
+
+// Known reports whether a status is declared.
+func (workflow Workflow) Known(status string) bool {
+	_, ok := workflow.index[status]
+	return ok
+}
+
+// Category returns a status's category. Statuses no longer declared (for
+// example after a config change) keep the built-in meaning of their name.
+func (workflow Workflow) Category(status string) string {
+	if index, ok := workflow.index[status]; ok {
+		return workflow.statuses[index].Category
+	}
+	switch status {
+	case StatusClosed:
+		return StatusCategoryDone
+	case StatusInProgress:
+		return StatusCategoryActive
+	default:
+		return StatusCategoryTodo
+	}
+}
+
+// IsDone reports whether a status is in the done category.
+func (workflow Workflow) IsDone(status string) bool {
+	return workflow.Category(status) == StatusCategoryDone
+}
+
+// ParseStatus normalizes user input to a declared status, accepting hyphens
+// in place of underscores (in-progress).
+func (workflow Workflow) ParseStatus(input string) (string, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(input))
+	if workflow.Known(trimmed) {
+		return trimmed, nil
+	}
+	if underscored := strings.ReplaceAll(trimmed, "-", "_"); workflow.Known(underscored) {
+		return underscored, nil
+	}
+	return "", fmt.Errorf("unknown status %q (valid: %s)", input, strings.Join(workflow.Statuses(), ", "))
+}
+
+// CheckTransition reports whether an issue may move between two statuses.
+// Staying put is always allowed, as is leaving an undeclared status.
+func (workflow Workflow) CheckTransition(from, to string) error {
+	if !workflow.Known(to) {
+		return fmt.Errorf("unknown status %q (valid: %s)", to, strings.Join(workflow.Statuses(), ", "))
+	}
+	index, ok := workflow.index[from]
+	if from == to || !ok {
+		return nil
+	}
+	allowed := workflow.statuses[index].Transitions
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, next := range allowed {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("status %s cannot move to %s (allowed: %s)", from, to, strings.Join(allowed, ", "))
+}
+
+// StatusIcon returns the display icon for a status based on its category.
+func (workflow Workflow) StatusIcon(status string) string {
+	switch workflow.Category(status) {
+	case StatusCategoryActive:
+		return "◐"
+	case StatusCategoryDone:
+		return "●"
+	default:
+		return "○"
+	}
+}
@@ -0,0 +1,158 @@
+package pebbles
+
+import (
+	"strings"
+	"testing"
+)
+
+func testWorkflowStatuses() []StatusConfig {
+	return []StatusConfig{
+		{Name: "open", Category: "todo", Transitions: []string{"in_progress", "closed"}},
+		{Name: "in_progress", Category: "active", Transitions: []string{"review", "open"}},
+		{Name: "Review", Category: "active"},
+		{Name: "closed", Category: "done", Transitions: []string{"open"}},
+		{Name: "shipped", Category: "done"},
+	}
+}
+
+func TestNewWorkflowValidatesStatuses(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []StatusConfig
+		want     string
+	}{
+		{"missing closed", []StatusConfig{{Name: "open", Category: "todo"}}, "must declare closed"},
+		{"open done", []StatusConfig{{Name: "open", Category: "done"}, {Name: "closed", Category: "done"}}, "must declare open"},
+		{"bad category", []StatusConfig{{Name: "open", Category: "later"}}, "unknown category"},
+		{"duplicate", []StatusConfig{{Name: "open", Category: "todo"}, {Name: "OPEN", Category: "todo"}}, "declared twice"},
+		{"space", []StatusConfig{{Name: "in review", Category: "active"}}, "commas or spaces"},
+		{"unknown transition", []StatusConfig{{Name: "open", Category: "todo", Transitions: []string{"review"}}, {Name: "closed", Category: "done"}}, "unknown status"},
+	}
+	for _, tc := range cases {
+		if _, err := NewWorkflow(tc.statuses); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+	workflow, err := NewWorkflow(testWorkflowStatuses())
+	if err != nil {
+		t.Fatalf("new workflow: %v", err)
+	}
+	if got := strings.Join(workflow.StatusesIn(StatusCategoryDone), ","); got != "closed,shipped" {
+		t.Fatalf("unexpected done statuses: %s", got)
+	}
+	if status, err := workflow.ParseStatus("In-Progress"); err != nil || status != "in_progress" {
+		t.Fatalf("expected in_progress, got %q (%v)", status, err)
+	}
+	if _, err := workflow.ParseStatus("blocked"); err == nil || !strings.Contains(err.Error(), "review") {
+		t.Fatalf("expected unknown status error listing statuses, got %v", err)
+	}
+}
+
+func TestWorkflowCheckTransition(t *testing.T) {
+	workflow, err := NewWorkflow(testWorkflowStatuses())
+	if err != nil {
+		t.Fatalf("new workflow: %v", err)
+	}
+	allowed := [][2]string{
+		{"open", "in_progress"},
+		{"in_progress", "review"},
+		{"review", "shipped"}, // no transitions listed: anything goes
+		{"closed", "closed"},
+		{"blocked", "open"}, // undeclared statuses can always leave
+	}
+	for _, move := range allowed {
+		if err := workflow.CheckTransition(move[0], move[1]); err != nil {
+			t.Fatalf("expected %s -> %s allowed, got %v", move[0], move[1], err)
+		}
+	}
+	if err := workflow.CheckTransition("open", "review"); err == nil || !strings.Contains(err.Error(), "allowed: in_progress, closed") {
+		t.Fatalf("expected open -> review rejected, got %v", err)
+	}
+	if err := workflow.CheckTransition("open", "blocked"); err == nil {
+		t.Fatalf("expected unknown target status rejected")
+	}
+	if !workflow.IsDone("shipped") || workflow.IsDone("review") || !workflow.IsDone("closed") {
+		t.Fatalf("unexpected done categories")
+	}
+}
+
+func TestReadyTreatsConfiguredDoneStatusAsFinished(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Statuses = testWorkflowStatuses()
+	if err := WriteConfig(root, cfg); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-blocker", "Blocker", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewCreateEvent("pb-blocked", "Blocked", "", "task", "2024-01-01T00:00:01Z", 2),
+		NewDepAddEvent("pb-blocked", "pb-blocker", DepTypeBlocks, "2024-01-01T00:00:02Z"),
+		NewStatusEvent("pb-blocker", "shipped", "2024-01-01T00:00:03Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	ready, err := ListReadyIssues(root)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != "pb-blocked" {
+		t.Fatalf("expected only pb-blocked ready, got %+v", ready)
+	}
+}
+
+func TestDoneCategoryStatusStampsClosedAt(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-done", "Done", "", "task", "2024-01-01T00:00:00Z", 2),
+		NewStatusEventWithCategory("pb-done", "shipped", StatusCategoryDone, "2024-01-01T00:00:01Z"),
+		NewCreateEvent("pb-moved", "Moved", "", "task", "2024-01-01T00:00:02Z", 2),
+		NewResolvedCloseEvent("pb-moved", ResolutionWontFix, "Out of scope", "2024-01-01T00:00:03Z"),
+		NewStatusEventWithCategory("pb-moved", "shipped", StatusCategoryDone, "2024-01-01T00:00:04Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err := GetIssue(root, "pb-done")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.ClosedAt != "2024-01-01T00:00:01Z" {
+		t.Fatalf("expected shipped to stamp closed_at, got %q", issue.ClosedAt)
+	}
+	moved, _, err := GetIssue(root, "pb-moved")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if moved.ClosedAt != "2024-01-01T00:00:03Z" || moved.Resolution != ResolutionWontFix || moved.CloseReason != "Out of scope" {
+		t.Fatalf("expected the close to survive a move to shipped, got %q %q %q", moved.ClosedAt, moved.Resolution, moved.CloseReason)
+	}
+	if err := AppendEvent(root, NewStatusEventWithCategory("pb-done", StatusOpen, StatusCategoryTodo, "2024-01-01T00:00:05Z")); err != nil {
+		t.Fatalf("append reopen: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	issue, _, err = GetIssue(root, "pb-done")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.ClosedAt != "" {
+		t.Fatalf("expected reopen to clear closed_at, got %q", issue.ClosedAt)
+	}
+}
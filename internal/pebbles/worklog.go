@@ -0,0 +1,138 @@
+package pebbles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeSummary totals estimates and logged time across a set of issues.
+// Estimates in points and in time are kept apart because they do not mix.
+type TimeSummary struct {
+	Issues          int
+	LoggedMinutes   int
+	EstimateMinutes int
+	EstimatePoints  float64
+}
+
+// ParseEstimate normalizes an estimate: a bare number is story points ("3",
+// "0.5") and a duration ("90m", "4h", "1h30m") is time. "none" or an empty
+// input returns "" so callers can clear the estimate.
+func ParseEstimate(input string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(input))
+	if value == "" || value == "none" {
+		return "", nil
+	}
+	if points, err := strconv.ParseFloat(value, 64); err == nil {
+		if points <= 0 {
+			return "", fmt.Errorf("estimate must be positive, got %q", input)
+		}
+		return strconv.FormatFloat(points, 'f', -1, 64), nil
+	}
+	minutes, err := ParseWorkDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid estimate %q (use points like 3 or a duration like 4h)", input)
+	}
+	return FormatMinutes(minutes), nil
+}
+
+// EstimatePoints returns the points of a point estimate.
+func EstimatePoints(estimate string) (float64, bool) {
+	points, err := strconv.ParseFloat(estimate, 64)
+	return points, err == nil
+}
+
+// EstimateMinutes returns the length of a time estimate in minutes.
+func EstimateMinutes(estimate string) (int, bool) {
+	if _, ok := EstimatePoints(estimate); ok || estimate == "" {
+		return 0, false
+	}
+	minutes, err := ParseWorkDuration(estimate)
+	return minutes, err == nil
+}
+
+// ParseWorkDuration parses a duration such as "45m", "2h", or "1h30m" into
+// whole minutes. Durations must be at least one minute.
+func ParseWorkDuration(input string) (int, error) {
+	duration, err := time.ParseDuration(strings.ToLower(strings.TrimSpace(input)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use forms like 45m, 2h, or 1h30m)", input)
+	}
+	minutes := int(duration.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		return 0, fmt.Errorf("duration %q must be at least 1m", input)
+	}
+	return minutes, nil
+}
+
+// FormatMinutes renders minutes as hours and minutes, such as "1h30m".
+func FormatMinutes(minutes int) string {
+	hours, rest := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", rest)
+	case rest == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, rest)
+	}
+}
+
+// TimerMinutes returns the minutes a timer started at an RFC3339 timestamp has
+// run until now, counting any partial minute as one.
+func TimerMinutes(startedAt string, now time.Time) (int, error) {
+	started, err := time.Parse(time.RFC3339Nano, startedAt)
+	if err != nil {
+		return 0, fmt.Errorf("parse timer start: %w", err)
+	}
+	elapsed := now.Sub(started)
+	minutes := int((elapsed + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return minutes, nil
+}
+
+// SubtreeTime totals estimates and logged time for an issue and every issue
+// below it in the parent-child hierarchy. Each issue counts once, even when
+// it is reachable through more than one parent.
+func SubtreeTime(root, id string) (TimeSummary, error) {
+	if err := EnsureCache(root); err != nil {
+		return TimeSummary{}, err
+	}
+	db, err := openDB(DBPath(root))
+	if err != nil {
+		return TimeSummary{}, err
+	}
+	defer func() { _ = db.Close() }()
+	resolvedID, err := resolveIssueID(db, id)
+	if err != nil {
+		return TimeSummary{}, err
+	}
+	node, err := buildParentChildTree(db, resolvedID, make(map[string]bool))
+	if err != nil {
+		return TimeSummary{}, err
+	}
+	var summary TimeSummary
+	addSubtreeTime(&summary, node, make(map[string]bool))
+	return summary, nil
+}
+
+// addSubtreeTime adds a node and its unseen descendants to a summary.
+func addSubtreeTime(summary *TimeSummary, node DepNode, seen map[string]bool) {
+	if seen[node.Issue.ID] {
+		return
+	}
+	seen[node.Issue.ID] = true
+	summary.Issues++
+	summary.LoggedMinutes += node.Issue.LoggedMinutes
+	if points, ok := EstimatePoints(node.Issue.Estimate); ok {
+		summary.EstimatePoints += points
+	} else if minutes, ok := EstimateMinutes(node.Issue.Estimate); ok {
+		summary.EstimateMinutes += minutes
+	}
+	for _, child := range node.Dependencies {
+		addSubtreeTime(summary, child, seen)
+	}
+}
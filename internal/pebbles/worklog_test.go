@@ -0,0 +1,106 @@
+package pebbles
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseEstimate verifies point and time estimates normalize.
+func TestParseEstimate(t *testing.T) {
+	cases := map[string]string{
+		"3":     "3",
+		"0.5":   "0.5",
+		"90m":   "1h30m",
+		"4H":    "4h",
+		"none":  "",
+		"":      "",
+		"1h15m": "1h15m",
+	}
+	for input, want := range cases {
+		got, err := ParseEstimate(input)
+		if err != nil {
+			t.Fatalf("ParseEstimate(%q): %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ParseEstimate(%q) = %q, want %q", input, got, want)
+		}
+	}
+	for _, input := range []string{"0", "-2", "soon", "20s"} {
+		if _, err := ParseEstimate(input); err == nil {
+			t.Fatalf("expected ParseEstimate(%q) to fail", input)
+		}
+	}
+}
+
+// TestTimerMinutesRoundsUp verifies partial minutes count toward the log.
+func TestTimerMinutesRoundsUp(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	cases := map[string]int{
+		"2024-03-01T10:00:00Z": 1,
+		"2024-03-01T09:59:30Z": 1,
+		"2024-03-01T09:15:00Z": 45,
+		"2024-03-01T08:29:10Z": 91,
+	}
+	for startedAt, want := range cases {
+		got, err := TimerMinutes(startedAt, now)
+		if err != nil {
+			t.Fatalf("TimerMinutes(%q): %v", startedAt, err)
+		}
+		if got != want {
+			t.Fatalf("TimerMinutes(%q) = %d, want %d", startedAt, got, want)
+		}
+	}
+}
+
+// TestWorkLogAndSubtreeTime verifies timers, manual entries, and the rollup
+// over a parent-child subtree.
+func TestWorkLogAndSubtreeTime(t *testing.T) {
+	root := t.TempDir()
+	if err := InitProject(root); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	events := []Event{
+		NewCreateEvent("pb-epic", "Epic", "", "epic", "2024-01-01T00:00:00Z", 2),
+		NewCreateEvent("pb-epic.1", "Child", "", "task", "2024-01-01T00:00:01Z", 2),
+		NewCreateEvent("pb-epic.1.1", "Grandchild", "", "task", "2024-01-01T00:00:02Z", 2),
+		NewCreateEvent("pb-other", "Other", "", "task", "2024-01-01T00:00:03Z", 2),
+		NewDepAddEvent("pb-epic.1", "pb-epic", DepTypeParentChild, "2024-01-01T00:00:04Z"),
+		NewDepAddEvent("pb-epic.1.1", "pb-epic.1", DepTypeParentChild, "2024-01-01T00:00:05Z"),
+		NewEstimateEvent("pb-epic", "5", "2024-01-01T00:00:06Z"),
+		NewEstimateEvent("pb-epic.1", "2h", "2024-01-01T00:00:07Z"),
+		NewEstimateEvent("pb-epic.1.1", "30m", "2024-01-01T00:00:08Z"),
+		NewTimerStartEvent("pb-epic.1", "2024-01-01T09:00:00Z"),
+		NewWorkLogEvent("pb-epic.1", 40, "2024-01-01T09:00:00Z", "2024-01-01T09:40:00Z"),
+		NewWorkLogEvent("pb-epic.1.1", 20, "", "2024-01-01T10:00:00Z"),
+		NewTimerStartEvent("pb-epic.1.1", "2024-01-01T11:00:00Z"),
+		NewWorkLogEvent("pb-other", 60, "", "2024-01-01T11:00:00Z"),
+	}
+	if err := AppendEvents(root, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if err := RebuildCache(root); err != nil {
+		t.Fatalf("rebuild cache: %v", err)
+	}
+	child, _, err := GetIssue(root, "pb-epic.1")
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+	if child.LoggedMinutes != 40 || child.TimerStartedAt != "" || child.Estimate != "2h" {
+		t.Fatalf("expected 40m logged with the timer stopped, got %+v", child)
+	}
+	grandchild, _, err := GetIssue(root, "pb-epic.1.1")
+	if err != nil {
+		t.Fatalf("get grandchild: %v", err)
+	}
+	if grandchild.TimerStartedAt != "2024-01-01T11:00:00Z" {
+		t.Fatalf("expected a manual entry to leave the new timer running, got %q", grandchild.TimerStartedAt)
+	}
+	summary, err := SubtreeTime(root, "pb-epic")
+	if err != nil {
+		t.Fatalf("subtree time: %v", err)
+	}
+	want := TimeSummary{Issues: 3, LoggedMinutes: 60, EstimateMinutes: 150, EstimatePoints: 5}
+	if summary != want {
+		t.Fatalf("expected %+v, got %+v", want, summary)
+	}
+}